@@ -2,4 +2,12 @@ module github.com/mejroslav/golox
 
 go 1.22.2
 
-require github.com/lmittmann/tint v1.1.2
+require (
+	github.com/lmittmann/tint v1.1.2
+	github.com/peterh/liner v1.2.2
+)
+
+require (
+	github.com/mattn/go-runewidth v0.0.3 // indirect
+	golang.org/x/sys v0.0.0-20211117180635-dee7805ff2e1 // indirect
+)
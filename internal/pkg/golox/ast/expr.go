@@ -12,15 +12,26 @@ type ExprVisitor interface {
 	VisitBinaryExpr(expr *Binary) (any, error)
 	VisitCallExpr(expr *Call) (any, error)
 	VisitGetExpr(expr *Get) (any, error)
+	VisitOptionalGetExpr(expr *OptionalGet) (any, error)
+	VisitChainExpr(expr *Chain) (any, error)
+	VisitConditionalExpr(expr *Conditional) (any, error)
 	VisitSetExpr(expr *Set) (any, error)
 	VisitSuperExpr(expr *Super) (any, error)
 	VisitThisExpr(expr *This) (any, error)
 	VisitGroupingExpr(expr *Grouping) (any, error)
 	VisitLiteralExpr(expr *Literal) (any, error)
+	VisitInterpolationExpr(expr *Interpolation) (any, error)
 	VisitLogicalExpr(expr *Logical) (any, error)
 	VisitUnaryExpr(expr *Unary) (any, error)
 	VisitVariableExpr(expr *Variable) (any, error)
 	VisitAssignExpr(expr *Assign) (any, error)
+	VisitCompoundExpr(expr *Compound) (any, error)
+	VisitUpdateExpr(expr *Update) (any, error)
+	VisitLambdaExpr(expr *Lambda) (any, error)
+	VisitListExpr(expr *List) (any, error)
+	VisitMapExpr(expr *Map) (any, error)
+	VisitIndexExpr(expr *Index) (any, error)
+	VisitSetIndexExpr(expr *SetIndex) (any, error)
 }
 
 type Binary struct {
@@ -52,6 +63,33 @@ func (node *Get) Accept(visitor ExprVisitor) (any, error) {
 	return visitor.VisitGetExpr(node)
 }
 
+type OptionalGet struct {
+	Object Expr
+	Name   *token.Token
+}
+
+func (node *OptionalGet) Accept(visitor ExprVisitor) (any, error) {
+	return visitor.VisitOptionalGetExpr(node)
+}
+
+type Chain struct {
+	Expression Expr
+}
+
+func (node *Chain) Accept(visitor ExprVisitor) (any, error) {
+	return visitor.VisitChainExpr(node)
+}
+
+type Conditional struct {
+	Condition  Expr
+	ThenBranch Expr
+	ElseBranch Expr
+}
+
+func (node *Conditional) Accept(visitor ExprVisitor) (any, error) {
+	return visitor.VisitConditionalExpr(node)
+}
+
 type Set struct {
 	Object Expr
 	Name   *token.Token
@@ -95,6 +133,14 @@ func (node *Literal) Accept(visitor ExprVisitor) (any, error) {
 	return visitor.VisitLiteralExpr(node)
 }
 
+type Interpolation struct {
+	Parts []Expr
+}
+
+func (node *Interpolation) Accept(visitor ExprVisitor) (any, error) {
+	return visitor.VisitInterpolationExpr(node)
+}
+
 type Logical struct {
 	Left     Expr
 	Operator *token.Token
@@ -130,3 +176,72 @@ type Assign struct {
 func (node *Assign) Accept(visitor ExprVisitor) (any, error) {
 	return visitor.VisitAssignExpr(node)
 }
+
+type Compound struct {
+	Target   Expr
+	Operator *token.Token
+	Value    Expr
+}
+
+func (node *Compound) Accept(visitor ExprVisitor) (any, error) {
+	return visitor.VisitCompoundExpr(node)
+}
+
+type Update struct {
+	Target   Expr
+	Operator *token.Token
+	Prefix   bool
+}
+
+func (node *Update) Accept(visitor ExprVisitor) (any, error) {
+	return visitor.VisitUpdateExpr(node)
+}
+
+type Lambda struct {
+	Keyword  *token.Token
+	Function *Function
+}
+
+func (node *Lambda) Accept(visitor ExprVisitor) (any, error) {
+	return visitor.VisitLambdaExpr(node)
+}
+
+type List struct {
+	Bracket  *token.Token
+	Elements []Expr
+}
+
+func (node *List) Accept(visitor ExprVisitor) (any, error) {
+	return visitor.VisitListExpr(node)
+}
+
+type Map struct {
+	Brace  *token.Token
+	Keys   []Expr
+	Values []Expr
+}
+
+func (node *Map) Accept(visitor ExprVisitor) (any, error) {
+	return visitor.VisitMapExpr(node)
+}
+
+type Index struct {
+	Object  Expr
+	Bracket *token.Token
+	Index   Expr
+}
+
+func (node *Index) Accept(visitor ExprVisitor) (any, error) {
+	return visitor.VisitIndexExpr(node)
+}
+
+type SetIndex struct {
+	Object  Expr
+	Bracket *token.Token
+	Index   Expr
+	Value   Expr
+}
+
+func (node *SetIndex) Accept(visitor ExprVisitor) (any, error) {
+	return visitor.VisitSetIndexExpr(node)
+}
@@ -17,8 +17,12 @@ type StmtVisitor interface {
 	VisitIfStmt(stmt *If) (any, error)
 	VisitWhileStmt(stmt *While) (any, error)
 	VisitBreakStmt(stmt *Break) (any, error)
+	VisitContinueStmt(stmt *Continue) (any, error)
+	VisitThrowStmt(stmt *Throw) (any, error)
+	VisitTryStmt(stmt *Try) (any, error)
 	VisitPrintStmt(stmt *Print) (any, error)
 	VisitVarStmt(stmt *Var) (any, error)
+	VisitImportStmt(stmt *Import) (any, error)
 }
 
 type Block struct {
@@ -77,8 +81,10 @@ func (node *If) Accept(visitor StmtVisitor) (any, error) {
 }
 
 type While struct {
+	Keyword   *token.Token
 	Condition Expr
 	Body      Stmt
+	Increment Expr
 }
 
 func (node *While) Accept(visitor StmtVisitor) (any, error) {
@@ -93,6 +99,34 @@ func (node *Break) Accept(visitor StmtVisitor) (any, error) {
 	return visitor.VisitBreakStmt(node)
 }
 
+type Continue struct {
+	Keyword *token.Token
+}
+
+func (node *Continue) Accept(visitor StmtVisitor) (any, error) {
+	return visitor.VisitContinueStmt(node)
+}
+
+type Throw struct {
+	Keyword *token.Token
+	Value   Expr
+}
+
+func (node *Throw) Accept(visitor StmtVisitor) (any, error) {
+	return visitor.VisitThrowStmt(node)
+}
+
+type Try struct {
+	Body        []Stmt
+	CatchName   *token.Token
+	CatchBody   []Stmt
+	FinallyBody []Stmt
+}
+
+func (node *Try) Accept(visitor StmtVisitor) (any, error) {
+	return visitor.VisitTryStmt(node)
+}
+
 type Print struct {
 	Expression Expr
 }
@@ -109,3 +143,14 @@ type Var struct {
 func (node *Var) Accept(visitor StmtVisitor) (any, error) {
 	return visitor.VisitVarStmt(node)
 }
+
+type Import struct {
+	Keyword *token.Token
+	Path    *token.Token
+	Alias   *token.Token
+	Names   []*token.Token
+}
+
+func (node *Import) Accept(visitor StmtVisitor) (any, error) {
+	return visitor.VisitImportStmt(node)
+}
@@ -36,6 +36,10 @@ func (a *AstPrinter) VisitLiteralExpr(expr *ast.Literal) (any, error) {
 	return fmt.Sprintf("%v", expr.Value), nil
 }
 
+func (a *AstPrinter) VisitInterpolationExpr(expr *ast.Interpolation) (any, error) {
+	return a.parenthesizeExprs("interpolate", expr.Parts...)
+}
+
 func (a *AstPrinter) VisitUnaryExpr(expr *ast.Unary) (any, error) {
 	return a.parenthesizeExprs(expr.Operator.Lexeme, expr.Right)
 }
@@ -55,6 +59,17 @@ func (a *AstPrinter) VisitVarStmt(stmt *ast.Var) (any, error) {
 	return "(var " + stmt.Name.Lexeme + ")", nil
 }
 
+func (a *AstPrinter) VisitImportStmt(stmt *ast.Import) (any, error) {
+	if stmt.Alias != nil {
+		return a.parenthesize("import", stmt.Path.Lexeme, "as", stmt.Alias.Lexeme)
+	}
+	parts := []any{stmt.Path.Lexeme}
+	for _, name := range stmt.Names {
+		parts = append(parts, name.Lexeme)
+	}
+	return a.parenthesize("from", parts...)
+}
+
 func (a *AstPrinter) VisitVariableExpr(expr *ast.Variable) (any, error) {
 	return expr.Name.Lexeme, nil
 }
@@ -79,6 +94,9 @@ func (a *AstPrinter) VisitLogicalExpr(expr *ast.Logical) (any, error) {
 }
 
 func (a *AstPrinter) VisitWhileStmt(stmt *ast.While) (any, error) {
+	if stmt.Increment != nil {
+		return a.parenthesize("while", stmt.Condition, stmt.Body, stmt.Increment)
+	}
 	return a.parenthesize("while", stmt.Condition, stmt.Body)
 }
 
@@ -97,6 +115,28 @@ func (a *AstPrinter) VisitFunctionStmt(stmt *ast.Function) (any, error) {
 	return a.parenthesize("fun", parts...)
 }
 
+func (a *AstPrinter) VisitCompoundExpr(expr *ast.Compound) (any, error) {
+	return a.parenthesizeExprs(expr.Operator.Lexeme, expr.Target, expr.Value)
+}
+
+func (a *AstPrinter) VisitUpdateExpr(expr *ast.Update) (any, error) {
+	if expr.Prefix {
+		return a.parenthesizeExprs("pre"+expr.Operator.Lexeme, expr.Target)
+	}
+	return a.parenthesizeExprs("post"+expr.Operator.Lexeme, expr.Target)
+}
+
+func (a *AstPrinter) VisitLambdaExpr(expr *ast.Lambda) (any, error) {
+	parts := []any{}
+	for _, param := range expr.Function.Params {
+		parts = append(parts, param)
+	}
+	for _, bodyStmt := range expr.Function.Body {
+		parts = append(parts, bodyStmt)
+	}
+	return a.parenthesize("lambda", parts...)
+}
+
 func (a *AstPrinter) VisitReturnStmt(stmt *ast.Return) (any, error) {
 	if stmt.Value != nil {
 		return a.parenthesizeExprs("return", stmt.Value)
@@ -116,6 +156,18 @@ func (a *AstPrinter) VisitGetExpr(expr *ast.Get) (any, error) {
 	return a.parenthesizeExprs("get "+expr.Name.Lexeme, expr.Object)
 }
 
+func (a *AstPrinter) VisitOptionalGetExpr(expr *ast.OptionalGet) (any, error) {
+	return a.parenthesizeExprs("get? "+expr.Name.Lexeme, expr.Object)
+}
+
+func (a *AstPrinter) VisitChainExpr(expr *ast.Chain) (any, error) {
+	return a.parenthesizeExprs("chain", expr.Expression)
+}
+
+func (a *AstPrinter) VisitConditionalExpr(expr *ast.Conditional) (any, error) {
+	return a.parenthesizeExprs("?:", expr.Condition, expr.ThenBranch, expr.ElseBranch)
+}
+
 func (a *AstPrinter) VisitSetExpr(expr *ast.Set) (any, error) {
 	return a.parenthesizeExprs("set "+expr.Name.Lexeme, expr.Object, expr.Value)
 }
@@ -132,6 +184,48 @@ func (a *AstPrinter) VisitBreakStmt(stmt *ast.Break) (any, error) {
 	return "(break)", nil
 }
 
+func (a *AstPrinter) VisitContinueStmt(stmt *ast.Continue) (any, error) {
+	return "(continue)", nil
+}
+
+func (a *AstPrinter) VisitListExpr(expr *ast.List) (any, error) {
+	return a.parenthesizeExprs("list", expr.Elements...)
+}
+
+func (a *AstPrinter) VisitMapExpr(expr *ast.Map) (any, error) {
+	entries := []ast.Expr{}
+	for i := range expr.Keys {
+		entries = append(entries, expr.Keys[i], expr.Values[i])
+	}
+	return a.parenthesizeExprs("map", entries...)
+}
+
+func (a *AstPrinter) VisitIndexExpr(expr *ast.Index) (any, error) {
+	return a.parenthesizeExprs("index", expr.Object, expr.Index)
+}
+
+func (a *AstPrinter) VisitSetIndexExpr(expr *ast.SetIndex) (any, error) {
+	return a.parenthesizeExprs("set-index", expr.Object, expr.Index, expr.Value)
+}
+
+func (a *AstPrinter) VisitThrowStmt(stmt *ast.Throw) (any, error) {
+	return a.parenthesizeExprs("throw", stmt.Value)
+}
+
+func (a *AstPrinter) VisitTryStmt(stmt *ast.Try) (any, error) {
+	body, _ := a.parenthesizeStmts("block", stmt.Body...)
+	result := "(try " + body
+	if stmt.CatchName != nil {
+		catch, _ := a.parenthesizeStmts("catch "+stmt.CatchName.Lexeme, stmt.CatchBody...)
+		result += " " + catch
+	}
+	if stmt.FinallyBody != nil {
+		finally, _ := a.parenthesizeStmts("finally", stmt.FinallyBody...)
+		result += " " + finally
+	}
+	return result + ")", nil
+}
+
 // Helper methods
 
 func (a *AstPrinter) parenthesizeExprs(name string, exprs ...ast.Expr) (string, error) {
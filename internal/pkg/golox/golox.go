@@ -7,6 +7,8 @@ import (
 	"log/slog"
 	"os"
 
+	"github.com/mejroslav/golox/internal/pkg/golox/interpreter"
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
 	"github.com/mejroslav/golox/internal/pkg/golox/runner"
 
 	"github.com/lmittmann/tint"
@@ -20,10 +22,18 @@ func Main() {
 	logLevel := flag.String("log-level", "info", "Set the logging level (debug, info, warn, error)")
 	showTokens := flag.Bool("show-tokens", false, "Display tokens during scanning")
 	showAST := flag.Bool("show-ast", false, "Display AST after parsing")
+	diagnostics := flag.String("diagnostics", "human", "Set the format of error messages on stderr (human, json)")
+	maxCallDepth := flag.Int("max-call-depth", interpreter.DefaultMaxCallDepth, "Set the maximum number of nested calls before a stack overflow error, 0 for no limit")
+	timeout := flag.Duration("timeout", 0, "Stop programs running longer than the given duration, e.g. 5s (0 for no limit)")
+	maxSteps := flag.Int("max-steps", 0, "Stop programs executing more statements and expressions than the given number (0 for no limit)")
+	maxAllocations := flag.Int("max-allocations", 0, "Stop programs creating more instances, lists, maps and strings than the given number (0 for no limit)")
+	warnings := flag.Bool("warnings", false, "Print warnings about likely mistakes, e.g. unused variables, before running a file")
 
 	flag.Usage = func() {
 		fmt.Fprintf(flag.CommandLine.Output(), "golox - Lox language interpreter in Go\n")
-		fmt.Fprintf(flag.CommandLine.Output(), "Usage: golox [options] <file>\n\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: golox [options] <file>\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "       golox check <file>...\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "       golox explain [code]\n\n")
 		fmt.Fprintf(flag.CommandLine.Output(), "Options:\n")
 		flag.PrintDefaults()
 	}
@@ -53,8 +63,23 @@ func Main() {
 		TimeFormat: "2006-01-02 15:04:05.000",
 	})))
 
+	if err := runner.SetDiagnosticsFormat(*diagnostics); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid diagnostics format: %s\n", *diagnostics)
+		os.Exit(1)
+	}
+	runner.SetWarnings(*warnings)
+	runner.SetMaxCallDepth(*maxCallDepth)
+	runner.SetLimits(interpreter.Limits{Timeout: *timeout, MaxSteps: *maxSteps, MaxAllocations: *maxAllocations})
+
 	slog.Debug("Starting golox interpreter")
 	args := flag.Args()
+	if len(args) > 0 && args[0] == "explain" {
+		os.Exit(explain(args[1:]))
+	}
+	if len(args) > 0 && args[0] == "check" {
+		os.Exit(check(args[1:], *diagnostics == "json"))
+	}
+
 	if len(args) < 1 {
 		runner.RunPrompt()
 		os.Exit(0)
@@ -62,9 +87,61 @@ func Main() {
 
 	filePath := args[0]
 	if err := runner.RunFile(filePath, *showTokens, *showAST); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		// In JSON mode stderr only contains the diagnostics themselves.
+		if *diagnostics != "json" || !runner.IsReported(err) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
 		os.Exit(1)
 	}
 
 	slog.Debug("Execution completed successfully")
 }
+
+// check reports the errors and warnings of the given files without running them.
+// It returns the exit code of the command, 1 if any problem was found.
+func check(paths []string, json bool) int {
+	if len(paths) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: golox check <file>...\n")
+		return 1
+	}
+
+	status := 0
+	for _, path := range paths {
+		warnings, err := runner.CheckFile(path)
+		if err != nil {
+			if !json || !runner.IsReported(err) {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+			status = 1
+		} else if warnings > 0 {
+			status = 1
+		}
+	}
+	return status
+}
+
+// explain prints the explanations of the given error codes, or lists all codes
+// if none are given. It returns the exit code of the command.
+func explain(codes []string) int {
+	if len(codes) == 0 {
+		for _, code := range lox_error.Codes() {
+			fmt.Printf("%s  %s\n", code, lox_error.Catalog[code].Title)
+		}
+		return 0
+	}
+
+	status := 0
+	for i, code := range codes {
+		explanation, ok := lox_error.Explain(lox_error.Code(code))
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Unknown error code: %s\n", code)
+			status = 1
+			continue
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Print(explanation)
+	}
+	return status
+}
@@ -0,0 +1,77 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+	"github.com/mejroslav/golox/internal/pkg/golox/token"
+	"github.com/mejroslav/golox/internal/pkg/golox/types"
+)
+
+// DefaultMaxCallDepth is the default limit of nested calls, see SetMaxCallDepth.
+const DefaultMaxCallDepth = 10000
+
+// SetMaxCallDepth limits the number of nested calls of Lox functions and classes.
+// A call exceeding it raises a "Stack overflow" runtime error, which Lox code can
+// catch, before unbounded recursion exhausts the Go stack. A depth of 0 or less
+// disables the limit.
+func (i *Interpreter) SetMaxCallDepth(depth int) {
+	i.maxCallDepth = depth
+}
+
+// pushFrame records the call of the named function on the call stack. The
+// call site is the closing parenthesis of the call expression being evaluated,
+// or the given position if the function is not called from Lox code.
+//
+// It returns a stack overflow error at the call site if the call stack is full.
+func (i *Interpreter) pushFrame(function string, position token.Token) error {
+	if i.callSite != nil {
+		position = *i.callSite
+		i.callSite = nil
+	}
+	if i.maxCallDepth > 0 && len(i.frames) >= i.maxCallDepth {
+		err := lox_error.NewRuntimeError(position, lox_error.CodeStackOverflow, "Stack overflow.")
+		err.Help = fmt.Sprintf("calls can be nested at most %d times; check that the recursion ends", i.maxCallDepth)
+		err.Trace = i.stackTrace()
+		return err
+	}
+	i.frames = append(i.frames, lox_error.Frame{Function: function, Call: position})
+	return nil
+}
+
+// popFrame removes the innermost call from the call stack.
+func (i *Interpreter) popFrame() {
+	i.frames = i.frames[:len(i.frames)-1]
+}
+
+// stackTrace returns a copy of the current call stack.
+func (i *Interpreter) stackTrace() []lox_error.Frame {
+	if len(i.frames) == 0 {
+		return nil
+	}
+	trace := make([]lox_error.Frame, len(i.frames))
+	copy(trace, i.frames)
+	return trace
+}
+
+// withTrace attaches the current call stack to a runtime error that does not have one yet.
+// It must be called before the frame of the failing function is popped.
+func (i *Interpreter) withTrace(err error) error {
+	if runtimeErr, ok := err.(lox_error.RuntimeError); ok && runtimeErr.Trace == nil {
+		runtimeErr.Trace = i.stackTrace()
+		return runtimeErr
+	}
+	return err
+}
+
+// uncaughtError converts a thrown value that reached the top level into a runtime error.
+func uncaughtError(throwValue *types.ThrowValue) error {
+	var err lox_error.RuntimeError
+	if loxError, ok := throwValue.Value.(*LoxError); ok {
+		err = lox_error.NewRuntimeError(loxError.Token, lox_error.CodeUncaughtException, loxError.Message)
+	} else {
+		err = lox_error.NewRuntimeError(*throwValue.Keyword, lox_error.CodeUncaughtException, "Uncaught exception: "+stringify(throwValue.Value))
+	}
+	err.Trace = throwValue.Trace
+	return err
+}
@@ -0,0 +1,132 @@
+package interpreter_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/interpreter"
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+)
+
+func TestCallDepth(t *testing.T) {
+	const countdown = `fun f(n) { if (n > 0) f(n - 1); } `
+	tests := []struct {
+		name     string
+		depth    int
+		source   string
+		want     string
+		overflow bool // Whether the program stops with a stack overflow
+	}{
+		{
+			name:   "recursion at the limit",
+			depth:  50,
+			source: countdown + `f(49); print "done";`,
+			want:   "done\n",
+		},
+		{
+			name:     "recursion beyond the limit",
+			depth:    50,
+			source:   countdown + `f(50); print "done";`,
+			overflow: true,
+		},
+		{
+			name:     "unbounded recursion with the default limit",
+			depth:    interpreter.DefaultMaxCallDepth,
+			source:   `fun f() { f(); } f();`,
+			overflow: true,
+		},
+		{
+			name:     "recursive initializer",
+			depth:    50,
+			source:   `class A { init() { A(); } } A();`,
+			overflow: true,
+		},
+		{
+			name:     "recursive method",
+			depth:    50,
+			source:   `class A { m() { return this.m(); } } A().m();`,
+			overflow: true,
+		},
+		{
+			name:   "no limit",
+			depth:  0,
+			source: countdown + `f(20000); print "done";`,
+			want:   "done\n",
+		},
+		{
+			name:  "caught overflow unwinds the call stack",
+			depth: 50,
+			source: countdown + `fun g() { g(); }
+			try { g(); } catch (e) { print e.message; }
+			f(49); print "done";`,
+			want: "Stack overflow.\ndone\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			interp := interpreter.NewInterpreter()
+			interp.SetMaxCallDepth(test.depth)
+			got, err := runIn(t, interp, test.source)
+			if got != test.want {
+				t.Errorf("output %q, want %q", got, test.want)
+			}
+			if !test.overflow {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			runtimeErr, ok := err.(lox_error.RuntimeError)
+			if !ok || runtimeErr.Code != lox_error.CodeStackOverflow {
+				t.Fatalf("error %v, want a stack overflow", err)
+			}
+			if len(runtimeErr.Trace) != test.depth {
+				t.Errorf("trace of %d calls, want %d", len(runtimeErr.Trace), test.depth)
+			}
+		})
+	}
+}
+
+func TestStackTrace(t *testing.T) {
+	const program = `fun a() { b(); }
+fun b() {
+  %s
+}
+class C { m() { a(); } init() { this.m(); } }
+var f = () => C();
+f();`
+	trace := []string{"<anonymous> 7:3", "C 6:17", "m 5:40", "a 5:19", "b 1:13"}
+
+	tests := []struct {
+		name  string
+		error string // The statement failing in 'b'
+		code  lox_error.Code
+		trace []string
+	}{
+		{name: "runtime error", error: `nil();`, code: lox_error.CodeNotCallable, trace: trace},
+		{name: "uncaught exception", error: `throw "x";`, code: lox_error.CodeUncaughtException, trace: trace},
+		{name: "caught and rethrown", error: `try { nil(); } catch (e) { throw e; }`, code: lox_error.CodeUncaughtException, trace: trace},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := run(t, fmt.Sprintf(program, test.error))
+			runtimeErr, ok := err.(lox_error.RuntimeError)
+			if !ok || runtimeErr.Code != test.code {
+				t.Fatalf("error %v, want code %q", err, test.code)
+			}
+			got := []string{}
+			for _, frame := range runtimeErr.Trace {
+				got = append(got, fmt.Sprintf("%s %d:%d", frame.Function, frame.Call.Line, frame.Call.Column))
+			}
+			if !slices.Equal(got, test.trace) {
+				t.Errorf("trace %q, want %q", got, test.trace)
+			}
+			if runtimeErr.Token.Line != 3 {
+				t.Errorf("error at line %d, want 3", runtimeErr.Token.Line)
+			}
+		})
+	}
+}
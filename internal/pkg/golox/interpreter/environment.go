@@ -35,10 +35,7 @@ func (e *Environment) Get(name *token.Token) (any, error) {
 		return e.enclosing.Get(name)
 	}
 
-	return nil, lox_error.RuntimeError{
-		Token:   *name,
-		Message: "Undefined variable '" + name.Lexeme + "'.",
-	}
+	return nil, e.undefinedVariable(name)
 }
 
 // GetAt retrieves the value of a variable at a specific distance
@@ -49,6 +46,7 @@ func (e *Environment) GetAt(distance int, name string) (any, error) {
 		return value, nil
 	}
 	return nil, lox_error.RuntimeError{
+		Code:    lox_error.CodeUndefinedVariable,
 		Message: "Undefined variable '" + name + "'.",
 	}
 }
@@ -73,10 +71,7 @@ func (e *Environment) Assign(name *token.Token, value any) error {
 		return e.enclosing.Assign(name, value)
 	}
 
-	return lox_error.RuntimeError{
-		Token:   *name,
-		Message: "Undefined variable '" + name.Lexeme + "'.",
-	}
+	return e.undefinedVariable(name)
 }
 
 // AssignAt updates the value of a variable at a specific distance
@@ -87,10 +82,45 @@ func (e *Environment) AssignAt(distance int, name *token.Token, value any) error
 		environment.values[name.Lexeme] = value
 		return nil
 	}
-	return lox_error.RuntimeError{
-		Token:   *name,
-		Message: "Undefined variable '" + name.Lexeme + "'.",
+	return e.undefinedVariable(name)
+}
+
+// undefinedVariable returns the error for a variable that is not defined,
+// suggesting similar names visible from this environment.
+func (e *Environment) undefinedVariable(name *token.Token) error {
+	err := lox_error.NewRuntimeError(*name, lox_error.CodeUndefinedVariable, "Undefined variable '"+name.Lexeme+"'.")
+	err.Help = lox_error.DidYouMean(lox_error.Suggest(name.Lexeme, e.Names()))
+	return err
+}
+
+// Names returns the names of all variables visible from this environment,
+// including those of the enclosing environments.
+func (e *Environment) Names() []string {
+	names := []string{}
+	for environment := e; environment != nil; environment = environment.enclosing {
+		for name := range environment.values {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Global returns the outermost environment. It holds the global variables
+// of the module (or the main script) this environment belongs to.
+func (e *Environment) Global() *Environment {
+	for e.enclosing != nil {
+		e = e.enclosing
+	}
+	return e
+}
+
+// Values returns a copy of the variables defined directly in this environment.
+func (e *Environment) Values() map[string]any {
+	values := make(map[string]any, len(e.values))
+	for name, value := range e.values {
+		values[name] = value
 	}
+	return values
 }
 
 // GetEnclosing returns the enclosing environment.
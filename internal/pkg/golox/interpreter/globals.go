@@ -2,7 +2,12 @@ package interpreter
 
 import (
 	"fmt"
+	"io"
+	"strings"
 	"time"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+	"github.com/mejroslav/golox/internal/pkg/golox/token"
 )
 
 // Clock is a native function that returns the current time in seconds since the Unix epoch.
@@ -19,7 +24,8 @@ func (c *Clock) String() string {
 	return "<native fn clock>"
 }
 
-// Input is a native function that prompts the user for input and returns it as a string.
+// Input is a native function that prompts the user for input and returns the line read,
+// without the line break, or nil at the end of the input.
 type Input struct{}
 
 func (i *Input) Arity() int {
@@ -33,17 +39,34 @@ func (i *Input) Call(interpreter *Interpreter, arguments []any) (any, error) {
 			prompt = p
 		}
 	}
-	var input string
 	if prompt != "" {
-		print(prompt)
+		fmt.Fprint(interpreter.stdout, prompt)
+	}
+	line, err := interpreter.stdin.ReadString('\n')
+	if err == io.EOF && line == "" {
+		return nil, nil
 	}
-	_, err := fmt.Scanln(&input)
-	if err != nil {
-		return "", err
+	if err != nil && err != io.EOF {
+		return nil, lox_error.NewRuntimeError(interpreter.CallPosition(), lox_error.CodeNativeError, fmt.Sprintf("Cannot read input: %s.", err))
 	}
-	return input, nil
+	return strings.TrimRight(line, "\r\n"), nil
 }
 
 func (i *Input) String() string {
 	return "<native fn input>"
 }
+
+// ErrorConstructor is a native function that creates a new error value with the given message.
+type ErrorConstructor struct{}
+
+func (e *ErrorConstructor) Arity() int {
+	return 1
+}
+
+func (e *ErrorConstructor) Call(interpreter *Interpreter, arguments []any) (any, error) {
+	return NewLoxError(stringify(arguments[0]), token.Token{}), nil
+}
+
+func (e *ErrorConstructor) String() string {
+	return "<native fn Error>"
+}
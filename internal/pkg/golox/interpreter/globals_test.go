@@ -0,0 +1,55 @@
+package interpreter_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/interpreter"
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+)
+
+func TestInput(t *testing.T) {
+	tests := []struct {
+		name   string
+		stdin  io.Reader
+		source string
+		want   string
+		code   lox_error.Code
+	}{
+		{
+			name:   "lines",
+			stdin:  strings.NewReader("first\r\nsecond"),
+			source: `print input("> "); print input(""); print input("");`,
+			want:   "> first\nsecond\nnil\n",
+		},
+		{
+			name:   "read error",
+			stdin:  iotest.ErrReader(errors.New("broken pipe")),
+			source: `input("");`,
+			code:   lox_error.CodeNativeError,
+		},
+		{
+			name:   "read error can be caught",
+			stdin:  iotest.ErrReader(errors.New("broken pipe")),
+			source: `try { input(""); } catch (e) { print e.message; }`,
+			want:   "Cannot read input: broken pipe.\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			interp := interpreter.NewInterpreter()
+			interp.SetStdin(test.stdin)
+			got, err := runIn(t, interp, test.source)
+			if code := errorCode(err); code != test.code {
+				t.Errorf("error %v, want code %q", err, test.code)
+			}
+			if got != test.want {
+				t.Errorf("output %q, want %q", got, test.want)
+			}
+		})
+	}
+}
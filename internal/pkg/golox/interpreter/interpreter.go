@@ -1,7 +1,16 @@
 package interpreter
 
 import (
+	"bufio"
+	"context"
 	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/mejroslav/golox/internal/pkg/golox/ast"
 	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
@@ -11,43 +20,115 @@ import (
 
 // Interpreter interprets and executes Lox code.
 type Interpreter struct {
-	globals     *Environment     // The global environment
-	environment *Environment     // The current environment
-	locals      map[ast.Expr]int // Maps ast.Expressions to their scope depth
+	globals      *Environment          // The global environment
+	environment  *Environment          // The current environment
+	locals       map[ast.Expr]int      // Maps ast.Expressions to their scope depth
+	moduleLoader ModuleLoader          // Loads the statements of imported modules
+	modules      map[string]*LoxModule // Cache of already executed modules by absolute path
+	importStack  []string              // Paths of the modules currently being imported
+	frames       []lox_error.Frame     // The call stack of Lox functions, innermost call last
+	callSite     *token.Token          // Position of the call expression being evaluated
+	suggestions  map[ast.Expr][]string // Names similar to unresolved variables, found by the resolver
+	statements   []ast.Stmt            // The statements being executed, innermost last, to locate errors
+	maxCallDepth int                   // The maximum length of frames, unlimited if 0
+	limits       Limits                // The execution budgets of Interpret
+	ctx          context.Context       // The context of the running Interpret call, nil if none
+	steps        int                   // The number of steps executed by the running Interpret call
+	nextCheck    int                   // The step at which the limits are checked next
+	allocations  int                   // The number of objects created by the running Interpret call
+	stdin        *bufio.Reader         // Read by 'input()'
+	stdout       io.Writer             // Written by 'print' and 'input()'
 }
 
 func NewInterpreter() *Interpreter {
 	globals := NewEnvironment(nil)
+	defineNatives(globals)
 
-	// Add built-in functions to the global environment
+	environment := globals
+	return &Interpreter{
+		globals:      globals,
+		environment:  environment,
+		locals:       make(map[ast.Expr]int),
+		modules:      make(map[string]*LoxModule),
+		suggestions:  make(map[ast.Expr][]string),
+		maxCallDepth: DefaultMaxCallDepth,
+		stdin:        bufio.NewReader(os.Stdin),
+		stdout:       os.Stdout,
+	}
+}
+
+// defineNatives adds the built-in functions to a global environment.
+func defineNatives(globals *Environment) {
 	clockCallable := &Clock{}
 	globals.Define("clock", clockCallable)
 	inputCallable := &Input{}
 	globals.Define("input", inputCallable)
+	errorCallable := &ErrorConstructor{}
+	globals.Define("Error", errorCallable)
+}
 
-	environment := globals
-	return &Interpreter{
-		globals:     globals,
-		environment: environment,
-		locals:      make(map[ast.Expr]int),
-	}
+// SetModuleLoader sets the function used to load modules for 'import' statements.
+func (i *Interpreter) SetModuleLoader(loader ModuleLoader) {
+	i.moduleLoader = loader
+}
+
+// SetStdin sets the reader from which 'input()' reads lines, os.Stdin by default.
+func (i *Interpreter) SetStdin(r io.Reader) {
+	i.stdin = bufio.NewReader(r)
+}
+
+// SetStdout sets the writer to which 'print' writes, os.Stdout by default.
+func (i *Interpreter) SetStdout(w io.Writer) {
+	i.stdout = w
 }
 
 // Interpret interprets and executes a list of statements.
+//
+// It returns the value of the last statement if it is an expression statement, nil otherwise.
 func (i *Interpreter) Interpret(statements []ast.Stmt) (any, error) {
+	return i.InterpretContext(context.Background(), statements)
+}
+
+func (i *Interpreter) interpret(statements []ast.Stmt) (any, error) {
+	var value any
 	for _, stmt := range statements {
-		_, err := i.execute(stmt)
+		var err error
+		value, err = i.execute(stmt)
 		if err != nil {
+			if throwValue, ok := err.(*types.ThrowValue); ok {
+				return nil, uncaughtError(throwValue)
+			}
 			return nil, err
 		}
 	}
-	return nil, nil
+	return value, nil
+}
+
+// Globals returns the global environment of the interpreter.
+func (i *Interpreter) Globals() *Environment {
+	return i.globals
 }
 
 func (i *Interpreter) VisitLiteralExpr(e *ast.Literal) (any, error) {
 	return e.Value, nil
 }
 
+// VisitInterpolationExpr concatenates the string representations of all parts.
+func (i *Interpreter) VisitInterpolationExpr(e *ast.Interpolation) (any, error) {
+	if err := i.allocate(); err != nil {
+		return nil, err
+	}
+	var result strings.Builder
+	for _, part := range e.Parts {
+		value, err := i.evaluate(part)
+		if err != nil {
+			return nil, err
+		}
+		result.WriteString(stringify(value))
+	}
+	return result.String(), nil
+}
+
 func (i *Interpreter) VisitGroupingExpr(e *ast.Grouping) (any, error) {
 	return i.evaluate(e.Expression)
 }
@@ -60,10 +141,19 @@ func (i *Interpreter) VisitUnaryExpr(e *ast.Unary) (any, error) {
 
 	switch e.Operator.Type {
 	case token.MINUS:
-		i.checkFloat64Operand(e.Operator, right)
-		return -right.(float64), nil
+		number, err := i.checkFloat64Operand(e.Operator, right)
+		if err != nil {
+			return nil, err
+		}
+		return -number, nil
 	case token.BANG:
 		return !isTruthy(right), nil
+	case token.TILDE:
+		operand, ok := toInteger(right)
+		if !ok {
+			return nil, lox_error.NewRuntimeError(*e.Operator, lox_error.CodeInvalidOperand, "Operand of '~' must be an integer.")
+		}
+		return float64(^operand), nil
 	}
 
 	return nil, nil
@@ -79,56 +169,91 @@ func (i *Interpreter) VisitBinaryExpr(e *ast.Binary) (any, error) {
 		return nil, err
 	}
 
-	switch e.Operator.Type {
+	return i.binaryOperation(e.Operator, left, right)
+}
+
+// binaryOperation applies a binary operator to already evaluated operands.
+func (i *Interpreter) binaryOperation(operator *token.Token, left, right any) (any, error) {
+	switch operator.Type {
 	case token.PLUS:
 		if l, ok := left.(string); ok {
 			if r, ok := right.(string); ok {
+				if err := i.allocate(); err != nil {
+					return nil, err
+				}
 				return l + r, nil
-			} else {
-				err := lox_error.NewRuntimeError(*e.Operator, "Cannot concatenate string "+left.(string)+" with "+fmt.Sprintf("%T", right))
-				return nil, err
 			}
-		} else if _, ok := left.(float64); ok {
-			if _, ok := right.(float64); ok {
-				return left.(float64) + right.(float64), nil
-			} else {
-				err := lox_error.NewRuntimeError(*e.Operator, "Cannot add number "+fmt.Sprintf("%T", left)+" with "+fmt.Sprintf("%T", right))
-				return nil, err
+		} else if l, ok := left.(float64); ok {
+			if r, ok := right.(float64); ok {
+				return l + r, nil
 			}
-		} else {
-			err := lox_error.NewRuntimeError(*e.Operator, "Cannot add "+fmt.Sprintf("%T", left)+" with "+fmt.Sprintf("%T", right))
-			return nil, err
 		}
+		return nil, lox_error.NewRuntimeError(*operator, lox_error.CodeInvalidOperand,
+			"Operands of '"+operator.Lexeme+"' must be two numbers or two strings, got "+TypeName(left)+" and "+TypeName(right)+".")
+	case token.BANG_EQUAL:
+		return !isEqual(left, right), nil
+	case token.EQUAL_EQUAL:
+		return isEqual(left, right), nil
+	case token.AMPERSAND, token.PIPE, token.CARET, token.LESS_LESS, token.GREATER_GREATER:
+		return i.bitwiseOperation(operator, left, right)
+	}
+
+	l, r, err := i.checkFloat64Operands(operator, left, right)
+	if err != nil {
+		return nil, err
+	}
+	switch operator.Type {
 	case token.MINUS:
-		i.checkFloat64Operands(e.Operator, left, right)
-		return left.(float64) - right.(float64), nil
+		return l - r, nil
 	case token.STAR:
-		i.checkFloat64Operands(e.Operator, left, right)
-		return left.(float64) * right.(float64), nil
+		return l * r, nil
 	case token.SLASH:
-		i.checkFloat64Operands(e.Operator, left, right)
-		return left.(float64) / right.(float64), nil
+		return l / r, nil
+	case token.PERCENT:
+		return math.Mod(l, r), nil
+	case token.STAR_STAR:
+		return math.Pow(l, r), nil
+	case token.TILDE_SLASH:
+		return math.Floor(l / r), nil
 	case token.GREATER:
-		i.checkFloat64Operands(e.Operator, left, right)
-		return left.(float64) > right.(float64), nil
+		return l > r, nil
 	case token.GREATER_EQUAL:
-		i.checkFloat64Operands(e.Operator, left, right)
-		return left.(float64) >= right.(float64), nil
+		return l >= r, nil
 	case token.LESS:
-		i.checkFloat64Operands(e.Operator, left, right)
-		return left.(float64) < right.(float64), nil
+		return l < r, nil
 	case token.LESS_EQUAL:
-		i.checkFloat64Operands(e.Operator, left, right)
-		return left.(float64) <= right.(float64), nil
-	case token.BANG_EQUAL:
-		return !isEqual(left, right), nil
-	case token.EQUAL_EQUAL:
-		return isEqual(left, right), nil
+		return l <= r, nil
 	}
 
 	return nil, nil
 }
 
+// bitwiseOperation applies a bitwise or shift operator to integral operands.
+func (i *Interpreter) bitwiseOperation(operator *token.Token, left, right any) (any, error) {
+	l, lok := toInteger(left)
+	r, rok := toInteger(right)
+	if !lok || !rok {
+		return nil, lox_error.NewRuntimeError(*operator, lox_error.CodeInvalidOperand, "Operands of '"+operator.Lexeme+"' must be integers.")
+	}
+
+	switch operator.Type {
+	case token.AMPERSAND:
+		return float64(l & r), nil
+	case token.PIPE:
+		return float64(l | r), nil
+	case token.CARET:
+		return float64(l ^ r), nil
+	}
+
+	if r < 0 {
+		return nil, lox_error.NewRuntimeError(*operator, lox_error.CodeInvalidOperand, "Shift count must not be negative.")
+	}
+	if operator.Type == token.LESS_LESS {
+		return float64(l << r), nil
+	}
+	return float64(l >> r), nil
+}
+
 func (i *Interpreter) VisitExpressionStmt(e *ast.Expression) (any, error) {
 	return i.evaluate(e.Expression)
 }
@@ -138,7 +263,7 @@ func (i *Interpreter) VisitPrintStmt(e *ast.Print) (any, error) {
 	if err != nil {
 		return nil, err
 	}
-	fmt.Println(stringify(value))
+	fmt.Fprintln(i.stdout, stringify(value))
 	return nil, nil
 }
 
@@ -155,6 +280,27 @@ func (i *Interpreter) VisitVarStmt(e *ast.Var) (any, error) {
 	return nil, nil
 }
 
+func (i *Interpreter) VisitImportStmt(stmt *ast.Import) (any, error) {
+	module, err := i.importModule(stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	if stmt.Alias != nil {
+		i.environment.Define(stmt.Alias.Lexeme, module)
+		return nil, nil
+	}
+
+	for _, name := range stmt.Names {
+		value, err := module.Get(*name)
+		if err != nil {
+			return nil, err
+		}
+		i.environment.Define(name.Lexeme, value)
+	}
+	return nil, nil
+}
+
 func (i *Interpreter) VisitVariableExpr(e *ast.Variable) (any, error) {
 	return i.lookupVariable(*e.Name, e)
 }
@@ -164,7 +310,8 @@ func (i *Interpreter) lookupVariable(name token.Token, expr ast.Expr) (any, erro
 	if ok {
 		return i.environment.GetAt(distance, name.Lexeme)
 	} else {
-		return i.globals.Get(&name)
+		value, err := i.environment.Global().Get(&name)
+		return value, i.withSuggestions(err, expr, name.Lexeme)
 	}
 }
 
@@ -178,10 +325,10 @@ func (i *Interpreter) VisitAssignExpr(e *ast.Assign) (any, error) {
 	if ok {
 		err = i.environment.AssignAt(distance, e.Name, value)
 	} else {
-		err = i.globals.Assign(e.Name, value)
+		err = i.environment.Global().Assign(e.Name, value)
 	}
 	if err != nil {
-		return nil, err
+		return nil, i.withSuggestions(err, e, e.Name.Lexeme)
 	}
 	return value, nil
 }
@@ -200,7 +347,7 @@ func (i *Interpreter) VisitClassStmt(stmt *ast.Class) (any, error) {
 		var ok bool
 		superclass, ok = superclassValue.(*LoxClass)
 		if !ok {
-			return nil, lox_error.NewRuntimeError(*stmt.Superclass.Name, "Superclass '"+stmt.Superclass.Name.Lexeme+"' must be a class.")
+			return nil, lox_error.NewRuntimeError(*stmt.Superclass.Name, lox_error.CodeInvalidSuperclass, "Superclass '"+stmt.Superclass.Name.Lexeme+"' must be a class.")
 		}
 	}
 
@@ -256,6 +403,10 @@ func (i *Interpreter) VisitLogicalExpr(e *ast.Logical) (any, error) {
 		if isTruthy(left) {
 			return left, nil
 		}
+	} else if e.Operator.Type == token.QUESTION_QUESTION {
+		if left != nil {
+			return left, nil
+		}
 	} else {
 		if !isTruthy(left) {
 			return left, nil
@@ -267,6 +418,9 @@ func (i *Interpreter) VisitLogicalExpr(e *ast.Logical) (any, error) {
 
 func (i *Interpreter) VisitWhileStmt(stmt *ast.While) (any, error) {
 	for {
+		if err := i.step(); err != nil {
+			return nil, err
+		}
 		condition, err := i.evaluate(stmt.Condition)
 		if err != nil {
 			return nil, err
@@ -280,7 +434,16 @@ func (i *Interpreter) VisitWhileStmt(stmt *ast.While) (any, error) {
 				// Break out of the loop
 				break
 			}
-			return nil, err
+			if _, ok := err.(*types.ContinueValue); !ok {
+				return nil, err
+			}
+			// Continue with the increment and the next iteration
+		}
+		if stmt.Increment != nil {
+			_, err = i.evaluate(stmt.Increment)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 	return nil, nil
@@ -303,13 +466,14 @@ func (i *Interpreter) VisitCallExpr(e *ast.Call) (any, error) {
 
 	function, ok := callee.(LoxCallable)
 	if !ok {
-		return nil, lox_error.NewRuntimeError(*e.Paren, "Can only call functions and classes.")
+		return nil, lox_error.NewRuntimeError(*e.Paren, lox_error.CodeNotCallable, "Can only call functions and classes.")
 	}
 
-	if len(arguments) != function.Arity() {
-		return nil, lox_error.NewRuntimeError(*e.Paren, fmt.Sprintf("Expected %d arguments but got %d.", function.Arity(), len(arguments)))
+	if expected, ok := checkArity(function, len(arguments)); !ok {
+		return nil, lox_error.NewRuntimeError(*e.Paren, lox_error.CodeArityMismatch, fmt.Sprintf("Expected %s arguments but got %d.", expected, len(arguments)))
 	}
 
+	i.callSite = e.Paren
 	return function.Call(i, arguments)
 }
 
@@ -319,12 +483,76 @@ func (i *Interpreter) VisitGetExpr(e *ast.Get) (any, error) {
 		return nil, err
 	}
 
-	loxInstance, ok := object.(*LoxInstance)
-	if !ok {
-		return nil, lox_error.NewRuntimeError(*e.Name, "Only instances have properties.")
+	return i.getProperty(object, e.Name)
+}
+
+func (i *Interpreter) VisitOptionalGetExpr(e *ast.OptionalGet) (any, error) {
+	object, err := i.evaluate(e.Object)
+	if err != nil {
+		return nil, err
 	}
 
-	return loxInstance.Get(*e.Name)
+	if object == nil {
+		return nil, &types.ShortCircuitValue{Operator: e.Name}
+	}
+
+	return i.getProperty(object, e.Name)
+}
+
+func (i *Interpreter) VisitChainExpr(e *ast.Chain) (any, error) {
+	value, err := i.evaluate(e.Expression)
+	if _, ok := err.(*types.ShortCircuitValue); ok {
+		return nil, nil
+	}
+	return value, err
+}
+
+func (i *Interpreter) VisitConditionalExpr(e *ast.Conditional) (any, error) {
+	condition, err := i.evaluate(e.Condition)
+	if err != nil {
+		return nil, err
+	}
+	if isTruthy(condition) {
+		return i.evaluate(e.ThenBranch)
+	}
+	return i.evaluate(e.ElseBranch)
+}
+
+// getProperty retrieves a property or a built-in method of an object.
+func (i *Interpreter) getProperty(object any, name *token.Token) (any, error) {
+	switch object := object.(type) {
+	case *LoxInstance:
+		return object.Get(*name)
+	case *LoxList:
+		return object.Get(*name)
+	case *LoxMap:
+		return object.Get(*name)
+	case *LoxError:
+		return object.Get(*name)
+	case *LoxModule:
+		return object.Get(*name)
+	}
+
+	return nil, lox_error.NewRuntimeError(*name, lox_error.CodeNotAnInstance, "Only instances have properties.")
+}
+
+// PropertyNames returns the sorted names of the properties that can be accessed on object with '.'.
+func PropertyNames(object any) []string {
+	var names []string
+	switch object := object.(type) {
+	case *LoxInstance:
+		names = object.Properties()
+	case *LoxList:
+		names = object.Properties()
+	case *LoxMap:
+		names = object.Properties()
+	case *LoxError:
+		names = object.Properties()
+	case *LoxModule:
+		names = object.Properties()
+	}
+	sort.Strings(names)
+	return slices.Compact(names)
 }
 
 func (i *Interpreter) VisitSetExpr(e *ast.Set) (any, error) {
@@ -335,7 +563,7 @@ func (i *Interpreter) VisitSetExpr(e *ast.Set) (any, error) {
 
 	loxInstance, ok := object.(*LoxInstance)
 	if !ok {
-		return nil, lox_error.NewRuntimeError(*e.Name, "Only instances have fields.")
+		return nil, lox_error.NewRuntimeError(*e.Name, lox_error.CodeNotAnInstance, "Only instances have fields.")
 	}
 
 	value, err := i.evaluate(e.Value)
@@ -343,14 +571,100 @@ func (i *Interpreter) VisitSetExpr(e *ast.Set) (any, error) {
 		return nil, err
 	}
 
-	loxInstance.Set(*e.Name, value)
+	if err := loxInstance.Set(*e.Name, value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (i *Interpreter) VisitListExpr(e *ast.List) (any, error) {
+	if err := i.allocate(); err != nil {
+		return nil, err
+	}
+	elements := make([]any, 0, len(e.Elements))
+	for _, element := range e.Elements {
+		value, err := i.evaluate(element)
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, value)
+	}
+	return NewLoxList(elements), nil
+}
+
+func (i *Interpreter) VisitMapExpr(e *ast.Map) (any, error) {
+	if err := i.allocate(); err != nil {
+		return nil, err
+	}
+	loxMap := NewLoxMap()
+	for j := range e.Keys {
+		key, err := i.evaluate(e.Keys[j])
+		if err != nil {
+			return nil, err
+		}
+		if err := checkKey(*e.Brace, key); err != nil {
+			return nil, err
+		}
+		value, err := i.evaluate(e.Values[j])
+		if err != nil {
+			return nil, err
+		}
+		loxMap.Put(key, value)
+	}
+	return loxMap, nil
+}
+
+func (i *Interpreter) VisitIndexExpr(e *ast.Index) (any, error) {
+	object, err := i.evaluate(e.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := i.evaluate(e.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	indexable, ok := object.(LoxIndexable)
+	if !ok {
+		return nil, lox_error.NewRuntimeError(*e.Bracket, lox_error.CodeNotIndexable, "Only lists and maps can be indexed.")
+	}
+
+	return indexable.GetIndex(*e.Bracket, index)
+}
+
+func (i *Interpreter) VisitSetIndexExpr(e *ast.SetIndex) (any, error) {
+	object, err := i.evaluate(e.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := i.evaluate(e.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	indexable, ok := object.(LoxIndexable)
+	if !ok {
+		return nil, lox_error.NewRuntimeError(*e.Bracket, lox_error.CodeNotIndexable, "Only lists and maps can be indexed.")
+	}
+
+	value, err := i.evaluate(e.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	err = indexable.SetIndex(*e.Bracket, index, value)
+	if err != nil {
+		return nil, err
+	}
 	return value, nil
 }
 
 func (i *Interpreter) VisitSuperExpr(e *ast.Super) (any, error) {
 	distance, ok := i.locals[e]
 	if !ok {
-		return nil, lox_error.NewRuntimeError(*e.Method, "Undefined 'super' reference.")
+		return nil, lox_error.NewRuntimeError(*e.Method, lox_error.CodeInvalidSuper, "Undefined 'super' reference.")
 	}
 
 	superclassValue, err := i.environment.GetAt(distance, "super")
@@ -359,7 +673,7 @@ func (i *Interpreter) VisitSuperExpr(e *ast.Super) (any, error) {
 	}
 	superclass, ok := superclassValue.(*LoxClass)
 	if !ok {
-		return nil, lox_error.NewRuntimeError(*e.Method, "'super' is not a class.")
+		return nil, lox_error.NewRuntimeError(*e.Method, lox_error.CodeInvalidSuper, "'super' is not a class.")
 	}
 
 	// We can't access 'this' directly from the environment because 'this' is stored
@@ -370,15 +684,15 @@ func (i *Interpreter) VisitSuperExpr(e *ast.Super) (any, error) {
 	}
 	loxInstance, ok := objectValue.(*LoxInstance)
 	if !ok {
-		return nil, lox_error.NewRuntimeError(*e.Method, "'this' is not an instance.")
+		return nil, lox_error.NewRuntimeError(*e.Method, lox_error.CodeThisOutsideClass, "'this' is not an instance.")
 	}
 
-	method, ok := superclass.GetMethod(e.Method.Lexeme)
+	method, ok := superclass.bindMethod(e.Method.Lexeme, loxInstance)
 	if !ok {
-		return nil, lox_error.NewRuntimeError(*e.Method, fmt.Sprintf("Undefined property '%s'.", e.Method.Lexeme))
+		return nil, undefinedProperty(*e.Method, fmt.Sprintf("Undefined property '%s'.", e.Method.Lexeme), superclass.MethodNames())
 	}
 
-	return method.Bind(loxInstance), nil
+	return method, nil
 }
 
 func (i *Interpreter) VisitThisExpr(e *ast.This) (any, error) {
@@ -391,6 +705,120 @@ func (i *Interpreter) VisitFunctionStmt(stmt *ast.Function) (any, error) {
 	return nil, nil
 }
 
+// compoundOperators maps compound assignment operators to their binary operators.
+var compoundOperators = map[token.TokenType]token.TokenType{
+	token.PLUS_EQUAL:    token.PLUS,
+	token.MINUS_EQUAL:   token.MINUS,
+	token.STAR_EQUAL:    token.STAR,
+	token.SLASH_EQUAL:   token.SLASH,
+	token.PERCENT_EQUAL: token.PERCENT,
+}
+
+func (i *Interpreter) VisitCompoundExpr(e *ast.Compound) (any, error) {
+	operator := *e.Operator
+	operator.Type = compoundOperators[e.Operator.Type]
+
+	_, value, err := i.update(e.Target, func(current any) (any, error) {
+		right, err := i.evaluate(e.Value)
+		if err != nil {
+			return nil, err
+		}
+		return i.binaryOperation(&operator, current, right)
+	})
+	return value, err
+}
+
+func (i *Interpreter) VisitUpdateExpr(e *ast.Update) (any, error) {
+	old, value, err := i.update(e.Target, func(current any) (any, error) {
+		number, err := i.checkFloat64Operand(e.Operator, current)
+		if err != nil {
+			return nil, err
+		}
+		if e.Operator.Type == token.PLUS_PLUS {
+			return number + 1, nil
+		}
+		return number - 1, nil
+	})
+	if e.Prefix {
+		return value, err
+	}
+	return old, err
+}
+
+// update reads the current value of an assignment target, computes the new value
+// and stores it back. The object and index of the target are evaluated only once.
+// It returns both the old and the new value.
+func (i *Interpreter) update(target ast.Expr, compute func(current any) (any, error)) (any, any, error) {
+	switch target := target.(type) {
+	case *ast.Variable:
+		old, err := i.lookupVariable(*target.Name, target)
+		if err != nil {
+			return nil, nil, err
+		}
+		value, err := compute(old)
+		if err != nil {
+			return nil, nil, err
+		}
+		if distance, ok := i.locals[target]; ok {
+			err = i.environment.AssignAt(distance, target.Name, value)
+		} else {
+			err = i.environment.Global().Assign(target.Name, value)
+		}
+		return old, value, i.withSuggestions(err, target, target.Name.Lexeme)
+
+	case *ast.Get:
+		object, err := i.evaluate(target.Object)
+		if err != nil {
+			return nil, nil, err
+		}
+		loxInstance, ok := object.(*LoxInstance)
+		if !ok {
+			return nil, nil, lox_error.NewRuntimeError(*target.Name, lox_error.CodeNotAnInstance, "Only instances have fields.")
+		}
+		old, err := loxInstance.Get(*target.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		value, err := compute(old)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := loxInstance.Set(*target.Name, value); err != nil {
+			return nil, nil, err
+		}
+		return old, value, nil
+
+	case *ast.Index:
+		object, err := i.evaluate(target.Object)
+		if err != nil {
+			return nil, nil, err
+		}
+		index, err := i.evaluate(target.Index)
+		if err != nil {
+			return nil, nil, err
+		}
+		indexable, ok := object.(LoxIndexable)
+		if !ok {
+			return nil, nil, lox_error.NewRuntimeError(*target.Bracket, lox_error.CodeNotIndexable, "Only lists and maps can be indexed.")
+		}
+		old, err := indexable.GetIndex(*target.Bracket, index)
+		if err != nil {
+			return nil, nil, err
+		}
+		value, err := compute(old)
+		if err != nil {
+			return nil, nil, err
+		}
+		return old, value, indexable.SetIndex(*target.Bracket, index, value)
+	}
+
+	return nil, nil, fmt.Errorf("invalid assignment target %T", target)
+}
+
+func (i *Interpreter) VisitLambdaExpr(e *ast.Lambda) (any, error) {
+	return NewLambdaFunction(e, i.environment), nil
+}
+
 func (i *Interpreter) VisitReturnStmt(stmt *ast.Return) (any, error) {
 	var value any
 	var err error
@@ -407,11 +835,59 @@ func (i *Interpreter) VisitBreakStmt(stmt *ast.Break) (any, error) {
 	return nil, &types.BreakValue{Keyword: stmt.Keyword}
 }
 
+func (i *Interpreter) VisitContinueStmt(stmt *ast.Continue) (any, error) {
+	return nil, &types.ContinueValue{Keyword: stmt.Keyword}
+}
+
+func (i *Interpreter) VisitThrowStmt(stmt *ast.Throw) (any, error) {
+	value, err := i.evaluate(stmt.Value)
+	if err != nil {
+		return nil, err
+	}
+	if loxError, ok := value.(*LoxError); ok && loxError.Token.File == "" {
+		// Errors created by Error() get the position of the 'throw' statement.
+		loxError.Token = *stmt.Keyword
+	}
+	return nil, &types.ThrowValue{Keyword: stmt.Keyword, Value: value, Trace: i.stackTrace()}
+}
+
+func (i *Interpreter) VisitTryStmt(stmt *ast.Try) (any, error) {
+	_, err := i.executeBlock(stmt.Body, NewEnvironment(i.environment))
+
+	if err != nil && stmt.CatchName != nil {
+		if value, ok := caughtValue(err); ok {
+			environment := NewEnvironment(i.environment)
+			environment.Define(stmt.CatchName.Lexeme, value)
+			_, err = i.executeBlock(stmt.CatchBody, environment)
+		}
+	}
+
+	if stmt.FinallyBody != nil {
+		// The finally block runs however control leaves the try statement,
+		// including 'return', 'break' and 'continue'. If the finally block itself
+		// leaves early, that takes precedence over the pending error or jump.
+		_, finallyErr := i.executeBlock(stmt.FinallyBody, NewEnvironment(i.environment))
+		if finallyErr != nil {
+			return nil, finallyErr
+		}
+	}
+
+	return nil, err
+}
+
 // ---------------------------------------------------------------------
 // Execute a statement
 
 func (i *Interpreter) execute(stmt ast.Stmt) (any, error) {
-	return stmt.Accept(i)
+	// Popped without defer, so that after a panic the stack still holds the innermost statement.
+	i.statements = append(i.statements, stmt)
+	if err := i.step(); err != nil {
+		i.statements = i.statements[:len(i.statements)-1]
+		return nil, err
+	}
+	value, err := stmt.Accept(i)
+	i.statements = i.statements[:len(i.statements)-1]
+	return value, err
 }
 
 func (i *Interpreter) Resolve(e ast.Expr, depth int) error {
@@ -420,6 +896,9 @@ func (i *Interpreter) Resolve(e ast.Expr, depth int) error {
 }
 
 func (i *Interpreter) evaluate(e ast.Expr) (any, error) {
+	if err := i.step(); err != nil {
+		return nil, err
+	}
 	return e.Accept(i)
 }
 
@@ -442,6 +921,18 @@ func (i *Interpreter) executeBlock(statements []ast.Stmt, environment *Environme
 // ---------------------------------------------------------------------
 // Helpers
 
+// caughtValue returns the Lox value a catch clause receives for err. Only thrown
+// values and runtime errors can be caught; 'return', 'break' and 'continue' cannot.
+func caughtValue(err error) (any, bool) {
+	switch err := err.(type) {
+	case *types.ThrowValue:
+		return err.Value, true
+	case lox_error.RuntimeError:
+		return NewLoxError(err.Message, err.Token), true
+	}
+	return nil, false
+}
+
 func isEqual(a, b any) bool {
 	if a == nil && b == nil {
 		return true
@@ -462,10 +953,15 @@ func isTruthy(object any) bool {
 	return true
 }
 
+// Stringify converts a Lox value to the string representation used by 'print'.
+func Stringify(object any) string {
+	return stringify(object)
+}
+
 // stringify converts an object to its string representation.
 //
 // For nil, it returns "nil".
-// For float64, it removes the decimal part if it's zero.
+// For float64, it removes the decimal part if it's zero and avoids the exponent for integers.
 // For bool, it returns "true" or "false".
 // For string, it returns the string itself.
 // For other types, it uses fmt.Sprintf to convert to string.
@@ -475,6 +971,10 @@ func stringify(object any) string {
 	}
 	switch v := object.(type) {
 	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1e21 {
+			// Print integers without an exponent, e.g. 1000000 instead of 1e+06.
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		}
 		s := fmt.Sprintf("%v", v)
 		if s[max(0, len(s)-2):] == ".0" {
 			s = s[:len(s)-2]
@@ -492,21 +992,88 @@ func stringify(object any) string {
 	}
 }
 
-// checkFloat64Operand checks if the operand is float64, returns an error if not.
-func (i *Interpreter) checkFloat64Operand(operator *token.Token, operand any) error {
-	if _, ok := operand.(float64); !ok {
-		return lox_error.NewRuntimeError(*operator, "Operand must be a number.")
+// toInteger converts a number without a fractional part to int64.
+func toInteger(value any) (int64, bool) {
+	number, ok := value.(float64)
+	if !ok || number != math.Trunc(number) || number < math.MinInt64 || number >= math.MaxInt64 {
+		return 0, false
 	}
-	return nil
+	return int64(number), true
+}
+
+// repr converts an object to its string representation for use inside collections.
+//
+// Unlike stringify, strings are quoted so that they can be told apart from other values.
+func repr(object any) string {
+	return reprVisiting(object, map[any]bool{})
+}
+
+// collection is a value that contains other values and so can contain itself.
+type collection interface {
+	// format returns the string representation of the collection. Collections in
+	// visiting are being formatted already and are printed abbreviated, e.g. [...].
+	format(visiting map[any]bool) string
+}
+
+// reprVisiting is repr for an object inside the collections in visiting.
+func reprVisiting(object any, visiting map[any]bool) string {
+	switch v := object.(type) {
+	case string:
+		return fmt.Sprintf("%q", v)
+	case collection:
+		return v.format(visiting)
+	}
+	return stringify(object)
 }
 
-// checkFloat64Operands checks if both operands are float64, returns an error if not.
-func (i *Interpreter) checkFloat64Operands(operator *token.Token, left, right any) error {
-	if _, ok := left.(float64); !ok {
-		return lox_error.NewRuntimeError(*operator, "Operand "+left.(string)+" must be a number.")
+// checkFloat64Operand returns the operand as float64, or an error if it is not a number.
+func (i *Interpreter) checkFloat64Operand(operator *token.Token, operand any) (float64, error) {
+	number, ok := operand.(float64)
+	if !ok {
+		return 0, lox_error.NewRuntimeError(*operator, lox_error.CodeInvalidOperand,
+			"Operand of '"+operator.Lexeme+"' must be a number, got "+TypeName(operand)+".")
 	}
-	if _, ok := right.(float64); !ok {
-		return lox_error.NewRuntimeError(*operator, "Operand "+right.(string)+" must be a number.")
+	return number, nil
+}
+
+// checkFloat64Operands returns both operands as float64, or an error if either is not a number.
+func (i *Interpreter) checkFloat64Operands(operator *token.Token, left, right any) (float64, float64, error) {
+	l, lok := left.(float64)
+	r, rok := right.(float64)
+	if !lok || !rok {
+		return 0, 0, lox_error.NewRuntimeError(*operator, lox_error.CodeInvalidOperand,
+			"Operands of '"+operator.Lexeme+"' must be numbers, got "+TypeName(left)+" and "+TypeName(right)+".")
+	}
+	return l, r, nil
+}
+
+// TypeName returns the name of the Lox type of a value, such as "number" or
+// "instance of Point", for error messages.
+func TypeName(object any) string {
+	switch v := object.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case *LoxInstance:
+		return "instance of " + v.Class.Name
+	case *LoxClass:
+		return "class"
+	case *LoxList:
+		return "list"
+	case *LoxMap:
+		return "map"
+	case *LoxError:
+		return "error"
+	case *LoxModule:
+		return "module"
+	case LoxCallable:
+		return "function"
+	default:
+		return fmt.Sprintf("%T", v)
 	}
-	return nil
 }
@@ -0,0 +1,406 @@
+package interpreter_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/ast"
+	"github.com/mejroslav/golox/internal/pkg/golox/interpreter"
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+	"github.com/mejroslav/golox/internal/pkg/golox/parser"
+	"github.com/mejroslav/golox/internal/pkg/golox/resolver"
+	"github.com/mejroslav/golox/internal/pkg/golox/scanner"
+)
+
+// load scans, parses and resolves a program for the interpreter and fails the test on errors.
+func load(t *testing.T, interp *interpreter.Interpreter, file string, source string) []ast.Stmt {
+	t.Helper()
+	codeScanner := scanner.NewCodeScanner(1, file)
+	tokens, scanErr := codeScanner.Run(source)
+	if scanErr {
+		t.Fatalf("scanning errors: %v", codeScanner.Errors())
+	}
+	parser := parser.NewParser(tokens)
+	statements, parseErr := parser.Parse()
+	if parseErr {
+		t.Fatalf("parsing errors: %v", parser.Errors())
+	}
+	resolver := resolver.NewResolver(interp)
+	resolver.SetSuppressions(codeScanner.Suppressions())
+	statements, resolveErr := resolver.Resolve(statements)
+	if resolveErr {
+		t.Fatalf("resolving errors: %v", resolver.Errors())
+	}
+	return statements
+}
+
+// run runs a program in a new interpreter and returns what it printed.
+func run(t *testing.T, source string) (string, error) {
+	t.Helper()
+	return runIn(t, interpreter.NewInterpreter(), source)
+}
+
+// runIn runs a program in the given interpreter and returns what it printed.
+func runIn(t *testing.T, interp *interpreter.Interpreter, source string) (string, error) {
+	t.Helper()
+	var stdout strings.Builder
+	interp.SetStdout(&stdout)
+	_, err := interp.Interpret(load(t, interp, "test.lox", source))
+	return stdout.String(), err
+}
+
+// programTest is a program with its expected output and, if code is set, the
+// code of the error that stops it.
+type programTest struct {
+	name   string
+	source string
+	want   string
+	code   lox_error.Code
+}
+
+func runProgramTests(t *testing.T, tests []programTest) {
+	t.Helper()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := run(t, test.source)
+			if code := errorCode(err); code != test.code {
+				t.Errorf("error %v, want code %q", err, test.code)
+			}
+			if got != test.want {
+				t.Errorf("output %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+// errorCode returns the code of the diagnostic of an error, or "" if there is none.
+func errorCode(err error) lox_error.Code {
+	diagnosable, ok := err.(lox_error.Diagnosable)
+	if !ok {
+		return ""
+	}
+	return diagnosable.Diagnostic().Code
+}
+
+func TestContinue(t *testing.T) {
+	runProgramTests(t, []programTest{
+		{
+			name:   "for runs the increment",
+			source: `for (var i = 0; i < 5; i = i + 1) { if (i == 2) continue; print i; }`,
+			want:   "0\n1\n3\n4\n",
+		},
+		{
+			name:   "for without increment",
+			source: `var i = 0; for (; i < 4;) { i = i + 1; if (i % 2 == 0) continue; print i; }`,
+			want:   "1\n3\n",
+		},
+		{
+			name:   "while",
+			source: `var i = 0; while (i < 4) { i = i + 1; if (i == 3) continue; print i; }`,
+			want:   "1\n2\n4\n",
+		},
+		{
+			name: "inner loop",
+			source: `for (var i = 0; i < 2; i = i + 1) {
+				for (var j = 0; j < 3; j = j + 1) { if (j == 1) continue; print "${i}${j}"; }
+			}`,
+			want: "00\n02\n10\n12\n",
+		},
+		{
+			name: "closure captures the iteration",
+			source: `var fs = [];
+			for (var i = 0; i < 3; i = i + 1) { if (i == 1) continue; fs.push(() => i); }
+			print fs[0]();`,
+			want: "3\n",
+		},
+		{
+			name:   "break still leaves the loop",
+			source: `for (var i = 0; i < 5; i = i + 1) { if (i == 1) continue; if (i == 3) break; print i; }`,
+			want:   "0\n2\n",
+		},
+	})
+}
+
+func TestExceptions(t *testing.T) {
+	runProgramTests(t, []programTest{
+		{
+			name:   "catch a thrown value",
+			source: `try { throw "x"; print "not reached"; } catch (e) { print e; }`,
+			want:   "x\n",
+		},
+		{
+			name:   "catch a runtime error",
+			source: "try {\n  nil();\n} catch (e) { print e.message; print e.line; }",
+			want:   "Can only call functions and classes.\n2\n",
+		},
+		{
+			name:   "error values",
+			source: `var e = Error("m"); print e.message; try { throw Error("boom"); } catch (e) { print e; }`,
+			want:   "m\nError: boom\n",
+		},
+		{
+			name:   "finally after try and catch",
+			source: `try { print "t"; } finally { print "f"; } try { throw 1; } catch (e) { print "c"; } finally { print "f"; }`,
+			want:   "t\nf\nc\nf\n",
+		},
+		{
+			name:   "finally runs when leaving through return",
+			source: `fun f() { try { return "t"; } finally { print "f"; } } print f();`,
+			want:   "f\nt\n",
+		},
+		{
+			name:   "finally runs when leaving through continue and break",
+			source: `for (var i = 0; i < 3; i = i + 1) { try { if (i == 1) break; continue; } finally { print i; } }`,
+			want:   "0\n1\n",
+		},
+		{
+			name:   "return in finally overrides return",
+			source: `fun f() { try { return 1; } finally { return 2; } } print f();`,
+			want:   "2\n",
+		},
+		{
+			name:   "throw in finally overrides throw",
+			source: `try { try { throw "a"; } finally { throw "b"; } } catch (e) { print e; }`,
+			want:   "b\n",
+		},
+		{
+			name:   "break in finally discards throw",
+			source: `while (true) { try { throw "a"; } finally { break; } } print "after";`,
+			want:   "after\n",
+		},
+		{
+			name:   "throw in catch runs finally",
+			source: `try { try { throw "a"; } catch (e) { throw "c"; } finally { print "f"; } } catch (e) { print e; }`,
+			want:   "f\nc\n",
+		},
+		{
+			name:   "throw from a called function",
+			source: `fun f() { throw "inner"; } try { f(); } catch (e) { print e; }`,
+			want:   "inner\n",
+		},
+		{
+			name:   "uncaught",
+			source: `print "before"; throw {"k": 1};`,
+			want:   "before\n",
+			code:   lox_error.CodeUncaughtException,
+		},
+	})
+}
+
+func TestCompoundAssignment(t *testing.T) {
+	runProgramTests(t, []programTest{
+		{
+			name:   "operators",
+			source: `var a = 7; a += 3; print a; a -= 1; print a; a *= 2; print a; a /= 4; print a; a %= 2; print a;`,
+			want:   "10\n9\n18\n4.5\n0.5\n",
+		},
+		{
+			name:   "string concatenation",
+			source: `var s = "a"; s += "b"; print s;`,
+			want:   "ab\n",
+		},
+		{
+			name:   "value of the assignment",
+			source: `var a = 1; print a += 1; print a;`,
+			want:   "2\n2\n",
+		},
+		{
+			name:   "prefix and postfix",
+			source: `var a = 1; print a++; print a; print ++a; print a--; print --a;`,
+			want:   "1\n2\n3\n3\n1\n",
+		},
+		{
+			name: "subscript evaluated once",
+			source: `var calls = 0; var xs = [10, 20];
+			fun index() { calls += 1; return 0; }
+			xs[index()] += 5; print xs; print calls;
+			xs[index()]++; print xs; print calls;`,
+			want: "[15, 20]\n1\n[16, 20]\n2\n",
+		},
+		{
+			name: "object evaluated once",
+			source: `class C { init() { this.n = 1; } }
+			var calls = 0; var c = C();
+			fun get() { calls += 1; return c; }
+			get().n += 2; print c.n; print calls;
+			get().n--; print c.n; print calls;`,
+			want: "3\n1\n2\n2\n",
+		},
+		{
+			name:   "map entry",
+			source: `var m = {"k": 1}; m["k"] *= 3; m["k"]++; print m;`,
+			want:   "{\"k\": 4}\n",
+		},
+		{
+			name:   "local and captured variables",
+			source: `fun counter() { var n = 0; return () => ++n; } var next = counter(); next(); print next();`,
+			want:   "2\n",
+		},
+		{
+			name:   "increment of nil",
+			source: `var u; u++;`,
+			code:   lox_error.CodeInvalidOperand,
+		},
+		{
+			name:   "subtraction from a string",
+			source: `var s = "a"; s -= 1;`,
+			code:   lox_error.CodeInvalidOperand,
+		},
+	})
+}
+
+func TestArithmetic(t *testing.T) {
+	runProgramTests(t, []programTest{
+		{
+			name:   "modulo",
+			source: `print 7 % 3; print -7 % 3; print 5.5 % 2;`,
+			want:   "1\n-1\n1.5\n",
+		},
+		{
+			name:   "exponent is right-associative and binds tighter than unary minus",
+			source: `print 2 ** 3 ** 2; print -2 ** 2; print 2 ** -1;`,
+			want:   "512\n-4\n0.5\n",
+		},
+		{
+			name:   "floor division",
+			source: `print 7 ~/ 2; print -7 ~/ 2; print 1 ~/ 0;`,
+			want:   "3\n-4\n+Inf\n",
+		},
+		{
+			name:   "precedence",
+			source: `print 1 + 2 * 3 % 4; print 2 * 3 ** 2; print 1 | 2 & 3; print 1 + 1 << 2;`,
+			want:   "3\n18\n3\n8\n",
+		},
+		{
+			name:   "bitwise operators",
+			source: `print 6 & 3; print 6 | 3; print 6 ^ 3; print ~5; print 1 << 4; print -16 >> 2;`,
+			want:   "2\n7\n5\n-6\n16\n-4\n",
+		},
+		{
+			name:   "large integers are printed without an exponent",
+			source: `print 0xFF; print 0b1010; print 1_000_000 * 1000; print 2 ** 53;`,
+			want:   "255\n10\n1000000000\n9007199254740992\n",
+		},
+		{
+			name:   "bitwise operator on a fraction",
+			source: `print 1.5 & 1;`,
+			code:   lox_error.CodeInvalidOperand,
+		},
+		{
+			name:   "negative shift",
+			source: `print 1 << -1;`,
+			code:   lox_error.CodeInvalidOperand,
+		},
+		{
+			name:   "modulo of a string",
+			source: `print "a" % 2;`,
+			code:   lox_error.CodeInvalidOperand,
+		},
+		{
+			name:   "operand errors can be caught",
+			source: `try { print ~"a"; } catch (e) { print e.message; }`,
+			want:   "Operand of '~' must be an integer.\n",
+		},
+	})
+}
+
+func TestLambdas(t *testing.T) {
+	runProgramTests(t, []programTest{
+		{
+			name:   "anonymous function",
+			source: `var add = fun (a, b) { return a + b; }; print add(1, 2); print add;`,
+			want:   "3\n<fn anonymous at test.lox:1>\n",
+		},
+		{
+			name:   "arrow function",
+			source: `var double = (x) => x * 2; print double(4); print (() => "none")();`,
+			want:   "8\nnone\n",
+		},
+		{
+			name:   "argument",
+			source: `fun apply(f, v) { return f(v); } print apply((x) => x + 1, 1); print apply(fun (x) { return -x; }, 1);`,
+			want:   "2\n-1\n",
+		},
+		{
+			name:   "closure",
+			source: `var adders = []; for (var i = 0; i < 3; i = i + 1) { var j = i; adders.push((x) => x + j); } print adders[0](10); print adders[2](10);`,
+			want:   "10\n12\n",
+		},
+		{
+			name:   "arity",
+			source: `((a) => a)();`,
+			code:   lox_error.CodeArityMismatch,
+		},
+	})
+}
+
+func TestConditionalOperators(t *testing.T) {
+	runProgramTests(t, []programTest{
+		{
+			name:   "conditional",
+			source: `print true ? 1 : 2; print nil ? 1 : false ? 2 : 3; print 0 ? "zero is truthy" : "";`,
+			want:   "1\n3\nzero is truthy\n",
+		},
+		{
+			name: "null coalescing evaluates the right operand only for nil",
+			source: `var calls = 0; fun side() { calls += 1; return "b"; }
+			print nil ?? side(); print "a" ?? side(); print false ?? side(); print calls;`,
+			want: "b\na\nfalse\n1\n",
+		},
+		{
+			name:   "optional chaining on nil",
+			source: `var o = nil; print o?.x; print o?.m(); print o?.a.b.c; print o?.x ?? "default";`,
+			want:   "nil\nnil\nnil\ndefault\n",
+		},
+		{
+			name: "optional chaining on an instance",
+			source: `class P { init() { this.x = 1; this.next = nil; } m() { return "m"; } }
+			var p = P(); print p?.x; print p?.m(); print p.next?.x;`,
+			want: "1\nm\nnil\n",
+		},
+		{
+			name: "optional chaining skips the arguments",
+			source: `var calls = 0; fun side() { calls += 1; }
+			var o = nil; o?.m(side()); print calls;`,
+			want: "0\n",
+		},
+		{
+			name:   "property of nil",
+			source: `var o = nil; print o.x;`,
+			code:   lox_error.CodeNotAnInstance,
+		},
+	})
+}
+
+func TestOperandErrors(t *testing.T) {
+	tests := []struct {
+		expression string
+		message    string
+	}{
+		{`-"a"`, "Operand of '-' must be a number, got string."},
+		{`"a" < 1`, "Operands of '<' must be numbers, got string and number."},
+		{`nil > nil`, "Operands of '>' must be numbers, got nil and nil."},
+		{`1 + nil`, "Operands of '+' must be two numbers or two strings, got number and nil."},
+		{`"a" + 1`, "Operands of '+' must be two numbers or two strings, got string and number."},
+		{`[1] + [2]`, "Operands of '+' must be two numbers or two strings, got list and list."},
+		{`"a" * 2`, "Operands of '*' must be numbers, got string and number."},
+		{`{} - 1`, "Operands of '-' must be numbers, got map and number."},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			got, err := run(t, "try { print "+test.expression+"; } catch (e) { print e.message; }")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.message+"\n" {
+				t.Errorf("message %q, want %q", got, test.message)
+			}
+
+			_, err = run(t, "print "+test.expression+";")
+			if runtimeErr, ok := err.(lox_error.RuntimeError); !ok || runtimeErr.Code != lox_error.CodeInvalidOperand || runtimeErr.Token.Line != 1 {
+				t.Errorf("error %v, want code %q at line 1", err, lox_error.CodeInvalidOperand)
+			}
+		})
+	}
+}
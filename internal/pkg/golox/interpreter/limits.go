@@ -0,0 +1,152 @@
+package interpreter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/ast"
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+	"github.com/mejroslav/golox/internal/pkg/golox/types"
+)
+
+// Limits are the execution budgets of a call of Interpret. A zero value disables a limit.
+type Limits struct {
+	Timeout        time.Duration // The maximum wall-clock time
+	MaxSteps       int           // The maximum number of executed statements, evaluated expressions and loop iterations
+	MaxAllocations int           // The maximum number of created instances, lists, maps and strings
+}
+
+// contextCheckInterval is the number of steps after which the context is checked.
+const contextCheckInterval = 256
+
+// errTimeout is the cause of the cancellation of the context when Limits.Timeout passes.
+var errTimeout = errors.New("timeout")
+
+// SetLimits sets the execution budgets of the following calls of Interpret.
+func (i *Interpreter) SetLimits(limits Limits) {
+	i.limits = limits
+}
+
+// InterpretContext executes the statements like Interpret, but stops with a
+// lox_error.LimitError when ctx is done or when the statements exceed the limits
+// of the interpreter. The budgets start anew with every call.
+func (i *Interpreter) InterpretContext(ctx context.Context, statements []ast.Stmt) (any, error) {
+	return i.run(ctx, func() (any, error) {
+		return i.interpret(statements)
+	})
+}
+
+// CallContext calls a Lox function or class from Go with the given arguments,
+// within the limits of the interpreter like InterpretContext.
+func (i *Interpreter) CallContext(ctx context.Context, callee any, arguments []any) (any, error) {
+	function, ok := callee.(LoxCallable)
+	if !ok {
+		return nil, fmt.Errorf("cannot call %s", TypeName(callee))
+	}
+	if expected, ok := checkArity(function, len(arguments)); !ok {
+		return nil, fmt.Errorf("expected %s arguments but got %d", expected, len(arguments))
+	}
+
+	return i.run(ctx, func() (any, error) {
+		i.callSite = nil
+		value, err := function.Call(i, arguments)
+		if throwValue, ok := err.(*types.ThrowValue); ok {
+			return nil, uncaughtError(throwValue)
+		}
+		return value, err
+	})
+}
+
+// run starts the budgets of the limits and runs f with the given context. If
+// the interpreter is already running, e.g. when Go code called from Lox calls
+// back into Lox, f runs within the budgets and the context of the outer run.
+func (i *Interpreter) run(ctx context.Context, f func() (any, error)) (any, error) {
+	if i.ctx != nil {
+		return f()
+	}
+
+	if i.limits.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, i.limits.Timeout, errTimeout)
+		defer cancel()
+	}
+
+	i.ctx = ctx
+	i.steps = 0
+	i.nextCheck = 0
+	i.allocations = 0
+	defer func() {
+		i.ctx = nil
+	}()
+	return f()
+}
+
+// step counts an executed statement, an evaluated expression or a loop iteration,
+// and checks that the execution may go on. It is kept small so that it is inlined.
+func (i *Interpreter) step() error {
+	i.steps++
+	if i.steps >= i.nextCheck {
+		return i.checkLimits()
+	}
+	return nil
+}
+
+// checkLimits returns an error if the step limit is exceeded or the context is
+// done, and schedules the next check. Checking the context is relatively slow,
+// so it is done only every contextCheckInterval steps.
+func (i *Interpreter) checkLimits() error {
+	if i.limits.MaxSteps > 0 && i.steps > i.limits.MaxSteps {
+		return i.limitError(lox_error.LimitSteps, nil, fmt.Sprintf("Execution limit exceeded: more than %d steps.", i.limits.MaxSteps))
+	}
+
+	i.nextCheck = i.steps + contextCheckInterval
+	if i.limits.MaxSteps > 0 {
+		i.nextCheck = min(i.nextCheck, i.limits.MaxSteps+1)
+	}
+
+	if i.ctx == nil {
+		return nil
+	}
+	select {
+	case <-i.ctx.Done():
+		return i.contextError()
+	default:
+		return nil
+	}
+}
+
+// allocate counts a created instance, list, map or string.
+func (i *Interpreter) allocate() error {
+	i.allocations++
+	if i.limits.MaxAllocations > 0 && i.allocations > i.limits.MaxAllocations {
+		return i.limitError(lox_error.LimitAllocations, nil, fmt.Sprintf("Execution limit exceeded: more than %d allocations.", i.limits.MaxAllocations))
+	}
+	return nil
+}
+
+// contextError returns the error for a done context, telling apart the timeout
+// of the limits, the deadline of the context and the cancellation.
+func (i *Interpreter) contextError() error {
+	err := i.ctx.Err()
+	switch {
+	case errors.Is(context.Cause(i.ctx), errTimeout):
+		return i.limitError(lox_error.LimitTime, err, fmt.Sprintf("Execution limit exceeded: the time limit of %v has passed.", i.limits.Timeout))
+	case errors.Is(err, context.DeadlineExceeded):
+		return i.limitError(lox_error.LimitTime, err, "Execution limit exceeded: the deadline has passed.")
+	default:
+		return i.limitError(lox_error.LimitCanceled, err, "Execution canceled.")
+	}
+}
+
+func (i *Interpreter) limitError(limit lox_error.Limit, cause error, message string) error {
+	position, _ := i.Position()
+	return lox_error.LimitError{
+		Token:   position,
+		Limit:   limit,
+		Message: message,
+		Trace:   i.stackTrace(),
+		Cause:   cause,
+	}
+}
@@ -0,0 +1,175 @@
+package interpreter_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/interpreter"
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+)
+
+func TestLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits interpreter.Limits
+		ctx    func() (context.Context, context.CancelFunc)
+		source string
+		want   string
+		limit  lox_error.Limit // The exceeded limit, "" if the program ends
+		line   int             // The line of the limit error
+		cause  error           // The error of the context wrapped by the limit error
+	}{
+		{
+			name:   "within the limits",
+			limits: interpreter.Limits{MaxSteps: 1000, MaxAllocations: 10, Timeout: time.Minute},
+			source: `var xs = []; for (var i = 0; i < 5; i++) xs.push(i); print xs;`,
+			want:   "[0, 1, 2, 3, 4]\n",
+		},
+		{
+			name:   "steps in an empty while loop",
+			limits: interpreter.Limits{MaxSteps: 1000},
+			source: "print 1;\nwhile (true) {}",
+			want:   "1\n",
+			limit:  lox_error.LimitSteps,
+			line:   2,
+		},
+		{
+			name:   "steps in an empty for loop",
+			limits: interpreter.Limits{MaxSteps: 1000},
+			source: "\n\nfor (;;) {}",
+			limit:  lox_error.LimitSteps,
+			line:   3,
+		},
+		{
+			name:   "steps in a function",
+			limits: interpreter.Limits{MaxSteps: 1000},
+			source: "fun spin() {\n  while (true) {}\n}\nspin();",
+			limit:  lox_error.LimitSteps,
+			line:   2,
+		},
+		{
+			name:   "steps cannot be caught",
+			limits: interpreter.Limits{MaxSteps: 1000},
+			source: `try { while (true) {} } catch (e) { print "caught"; } finally { print "finally"; }`,
+			limit:  lox_error.LimitSteps,
+			line:   1,
+		},
+		{
+			name:   "allocations",
+			limits: interpreter.Limits{MaxAllocations: 100},
+			source: "var xs = [];\nwhile (true) xs.push([]);",
+			limit:  lox_error.LimitAllocations,
+			line:   2,
+		},
+		{
+			name:   "timeout",
+			limits: interpreter.Limits{Timeout: 10 * time.Millisecond},
+			source: "while (true) {}",
+			limit:  lox_error.LimitTime,
+			line:   1,
+			cause:  context.DeadlineExceeded,
+		},
+		{
+			name: "deadline of the context",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Millisecond)
+			},
+			source: "var i = 0;\nwhile (true) i++;",
+			limit:  lox_error.LimitTime,
+			line:   2,
+			cause:  context.DeadlineExceeded,
+		},
+		{
+			name: "cancellation",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(10*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			source: "while (true) {}",
+			limit:  lox_error.LimitCanceled,
+			line:   1,
+			cause:  context.Canceled,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.Background(), context.CancelFunc(func() {})
+			if test.ctx != nil {
+				ctx, cancel = test.ctx()
+			}
+			defer cancel()
+
+			interp := interpreter.NewInterpreter()
+			interp.SetLimits(test.limits)
+			var stdout strings.Builder
+			interp.SetStdout(&stdout)
+			_, err := interp.InterpretContext(ctx, load(t, interp, "test.lox", test.source))
+			if got := stdout.String(); got != test.want {
+				t.Errorf("output %q, want %q", got, test.want)
+			}
+			if test.limit == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			var limitErr lox_error.LimitError
+			if !errors.As(err, &limitErr) {
+				t.Fatalf("error %v, want a limit error", err)
+			}
+			if limitErr.Limit != test.limit {
+				t.Errorf("limit %q, want %q", limitErr.Limit, test.limit)
+			}
+			if limitErr.Token.File != "test.lox" || limitErr.Token.Line != test.line || limitErr.Token.Column == 0 {
+				t.Errorf("error at %s:%d:%d, want test.lox:%d", limitErr.Token.File, limitErr.Token.Line, limitErr.Token.Column, test.line)
+			}
+			if test.cause != nil && !errors.Is(err, test.cause) {
+				t.Errorf("error %v does not wrap %v", err, test.cause)
+			}
+		})
+	}
+}
+
+func TestLimitsStartAnew(t *testing.T) {
+	interp := interpreter.NewInterpreter()
+	interp.SetLimits(interpreter.Limits{MaxSteps: 500, MaxAllocations: 50})
+	statements := load(t, interp, "test.lox", `for (var i = 0; i < 20; i++) { var s = "${i}"; }`)
+	for run := 0; run < 3; run++ {
+		if _, err := interp.Interpret(statements); err != nil {
+			t.Fatalf("run %d: %v", run, err)
+		}
+	}
+}
+
+func TestCallContext(t *testing.T) {
+	interp := interpreter.NewInterpreter()
+	interp.SetLimits(interpreter.Limits{MaxSteps: 1000})
+	statements := load(t, interp, "test.lox", "fun add(a, b) { return a + b; }\nfun spin() {\n  while (true) {}\n}")
+	if _, err := interp.Interpret(statements); err != nil {
+		t.Fatal(err)
+	}
+	add, spin := interp.Globals().Values()["add"], interp.Globals().Values()["spin"]
+
+	value, err := interp.CallContext(context.Background(), add, []any{1.0, 2.0})
+	if err != nil || value != 3.0 {
+		t.Errorf("add(1, 2) = %v, %v, want 3", value, err)
+	}
+	if _, err := interp.CallContext(context.Background(), add, []any{1.0}); err == nil {
+		t.Error("add(1) succeeded, want an arity error")
+	}
+
+	var limitErr lox_error.LimitError
+	_, err = interp.CallContext(context.Background(), spin, nil)
+	if !errors.As(err, &limitErr) || limitErr.Limit != lox_error.LimitSteps {
+		t.Fatalf("spin() error %v, want a step limit error", err)
+	}
+	if limitErr.Token.Line != 3 || len(limitErr.Trace) != 1 || limitErr.Trace[0].Function != "spin" {
+		t.Errorf("error at line %d with trace %v, want line 3 in 'spin'", limitErr.Token.Line, limitErr.Trace)
+	}
+}
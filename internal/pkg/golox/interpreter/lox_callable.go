@@ -1,8 +1,29 @@
 package interpreter
 
+import (
+	"fmt"
+	"strconv"
+)
+
 // LoxCallable represents any callable entity in the Lox language,
 // such as functions and classes.
 type LoxCallable interface {
 	Arity() int                                                  // number of expected arguments
 	Call(interpreter *Interpreter, arguments []any) (any, error) // execute the callable
 }
+
+// VariadicCallable is a callable that accepts more arguments than its arity,
+// which is then the minimal number of arguments.
+type VariadicCallable interface {
+	LoxCallable
+	Variadic() bool // whether more arguments than the arity are accepted
+}
+
+// checkArity reports whether the callable accepts the number of arguments,
+// and describes the expected number for error messages, e.g. "2" or "at least 1".
+func checkArity(function LoxCallable, count int) (string, bool) {
+	if variadic, ok := function.(VariadicCallable); ok && variadic.Variadic() {
+		return fmt.Sprintf("at least %d", function.Arity()), count >= function.Arity()
+	}
+	return strconv.Itoa(function.Arity()), count == function.Arity()
+}
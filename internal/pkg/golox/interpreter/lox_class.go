@@ -5,6 +5,7 @@ type LoxClass struct {
 	Name       string                  // The name of the class
 	Superclass *LoxClass               // The superclass of the class, if any
 	Methods    map[string]*LoxFunction // The methods defined in the class
+	Native     *NativeClass            // The Go type bound to the class, nil for classes declared in Lox
 }
 
 func NewLoxClass(name string, superclass *LoxClass, methods map[string]*LoxFunction) *LoxClass {
@@ -25,17 +26,45 @@ func (lc *LoxClass) Arity() int {
 	if initializer, ok := lc.getInitializer(); ok {
 		return initializer.Arity()
 	}
+	if initializer, ok := lc.getNativeInitializer(); ok {
+		return initializer.arity
+	}
 	return 0
 }
 
+// Variadic reports whether the class accepts more arguments than its arity,
+// which is the case for a class bound to a variadic Go constructor.
+func (lc *LoxClass) Variadic() bool {
+	if _, ok := lc.getInitializer(); ok {
+		return false
+	}
+	initializer, ok := lc.getNativeInitializer()
+	return ok && initializer.variadic
+}
+
 // Call creates a new instance of the class and initializes it if there is an initializer.
 func (lc *LoxClass) Call(interpreter *Interpreter, arguments []any) (any, error) {
+	if err := interpreter.allocate(); err != nil {
+		return nil, err
+	}
 	instance := NewLoxInstance(lc)
-	if initializer, ok := lc.getInitializer(); ok {
-		_, err := initializer.Bind(instance).Call(interpreter, arguments)
-		if err != nil {
+	if native := lc.nativeClass(); native != nil {
+		instance.Native = native.New()
+	}
+	if initializer, ok := lc.getNativeInitializer(); ok {
+		if _, err := initializer.Bind(instance).Call(interpreter, arguments); err != nil {
 			return nil, err
 		}
+	} else if initializer, ok := lc.getInitializer(); ok {
+		if err := interpreter.pushFrame(lc.Name, initializer.position()); err != nil {
+			return nil, err
+		}
+		defer interpreter.popFrame()
+
+		_, err := initializer.Bind(instance).call(interpreter, arguments)
+		if err != nil {
+			return nil, interpreter.withTrace(err)
+		}
 	}
 	return instance, nil
 }
@@ -48,6 +77,61 @@ func (lc *LoxClass) getInitializer() (*LoxFunction, bool) {
 	return initializer, true
 }
 
+// getNativeInitializer returns the Go constructor of a class bound to a Go type.
+func (lc *LoxClass) getNativeInitializer() (*NativeMethod, bool) {
+	if lc.Native == nil {
+		return nil, false
+	}
+	initializer, ok := lc.Native.Methods["init"]
+	return initializer, ok
+}
+
+// nativeClass returns the Go type bound to the class or to its nearest superclass bound to one.
+func (lc *LoxClass) nativeClass() *NativeClass {
+	for class := lc; class != nil; class = class.Superclass {
+		if class.Native != nil {
+			return class.Native
+		}
+	}
+	return nil
+}
+
+// MethodNames returns the names of the methods of the class and its superclasses, except 'init'.
+func (lc *LoxClass) MethodNames() []string {
+	names := []string{}
+	for class := lc; class != nil; class = class.Superclass {
+		for name := range class.Methods {
+			if name != "init" {
+				names = append(names, name)
+			}
+		}
+		if class.Native != nil {
+			for name := range class.Native.Methods {
+				if name != "init" {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// bindMethod looks up a method by name, checking superclasses if necessary, and
+// binds it to the instance. Unlike GetMethod, it also finds methods implemented in Go.
+func (lc *LoxClass) bindMethod(name string, instance *LoxInstance) (LoxCallable, bool) {
+	for class := lc; class != nil; class = class.Superclass {
+		if method, ok := class.Methods[name]; ok {
+			return method.Bind(instance), true
+		}
+		if class.Native != nil {
+			if method, ok := class.Native.Methods[name]; ok {
+				return method.Bind(instance), true
+			}
+		}
+	}
+	return nil, false
+}
+
 // GetMethod looks up a method by name, checking superclasses if necessary.
 func (lc *LoxClass) GetMethod(name string) (*LoxFunction, bool) {
 	method, ok := lc.Methods[name]
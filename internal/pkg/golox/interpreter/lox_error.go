@@ -0,0 +1,48 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/token"
+)
+
+// LoxError represents an error value in the Lox language. Runtime errors caught
+// by a 'catch' clause are turned into LoxError values, and scripts can create
+// their own with the native function Error(message).
+type LoxError struct {
+	Message string
+	Token   token.Token // The position where the error occurred
+}
+
+func NewLoxError(message string, token token.Token) *LoxError {
+	return &LoxError{
+		Message: message,
+		Token:   token,
+	}
+}
+
+// String returns a string representation of the error.
+func (le *LoxError) String() string {
+	return "Error: " + le.Message
+}
+
+// Properties returns the names of the properties of the error.
+func (le *LoxError) Properties() []string {
+	return []string{"message", "file", "line", "column"}
+}
+
+// Get retrieves a read-only property of the error.
+func (le *LoxError) Get(name token.Token) (any, error) {
+	switch name.Lexeme {
+	case "message":
+		return le.Message, nil
+	case "file":
+		return le.Token.File, nil
+	case "line":
+		return float64(le.Token.Line), nil
+	case "column":
+		return float64(le.Token.Column), nil
+	}
+
+	return nil, undefinedProperty(name, fmt.Sprintf("Error has no property '%s'.", name.Lexeme), le.Properties())
+}
@@ -1,7 +1,10 @@
 package interpreter
 
 import (
+	"fmt"
+
 	"github.com/mejroslav/golox/internal/pkg/golox/ast"
+	"github.com/mejroslav/golox/internal/pkg/golox/token"
 	"github.com/mejroslav/golox/internal/pkg/golox/types"
 )
 
@@ -10,6 +13,7 @@ type LoxFunction struct {
 	Declaration   *ast.Function
 	Closure       *Environment
 	IsInitializer bool
+	Lambda        *ast.Lambda // The expression defining an anonymous function, nil otherwise
 }
 
 func NewLoxFunction(declaration *ast.Function, closure *Environment) *LoxFunction {
@@ -28,6 +32,15 @@ func NewInitializerFunction(declaration *ast.Function, closure *Environment) *Lo
 	}
 }
 
+func NewLambdaFunction(lambda *ast.Lambda, closure *Environment) *LoxFunction {
+	return &LoxFunction{
+		Declaration:   lambda.Function,
+		Closure:       closure,
+		IsInitializer: false,
+		Lambda:        lambda,
+	}
+}
+
 // Arity returns the number of parameters the function expects.
 func (lf *LoxFunction) Arity() int {
 	return len(lf.Declaration.Params)
@@ -35,11 +48,44 @@ func (lf *LoxFunction) Arity() int {
 
 // String returns a string representation of the function.
 func (lf *LoxFunction) String() string {
+	if lf.Lambda != nil {
+		return fmt.Sprintf("<fn anonymous at %s:%d>", lf.Lambda.Keyword.File, lf.Lambda.Keyword.Line)
+	}
 	return "<fn " + lf.Declaration.Name.Lexeme + ">"
 }
 
+// Name returns the name of the function shown in stack traces.
+func (lf *LoxFunction) Name() string {
+	if lf.Lambda != nil {
+		return "<anonymous>"
+	}
+	return lf.Declaration.Name.Lexeme
+}
+
+// position returns the token that starts the definition of the function.
+func (lf *LoxFunction) position() token.Token {
+	if lf.Lambda != nil {
+		return *lf.Lambda.Keyword
+	}
+	return *lf.Declaration.Name
+}
+
 // Call executes the function with the given arguments.
 func (lf *LoxFunction) Call(interpreter *Interpreter, arguments []any) (any, error) {
+	if err := interpreter.pushFrame(lf.Name(), lf.position()); err != nil {
+		return nil, err
+	}
+	defer interpreter.popFrame()
+
+	value, err := lf.call(interpreter, arguments)
+	if err != nil {
+		return nil, interpreter.withTrace(err)
+	}
+	return value, nil
+}
+
+// call executes the function body without recording a frame on the call stack.
+func (lf *LoxFunction) call(interpreter *Interpreter, arguments []any) (any, error) {
 	// Create a new environment for the function execution
 	// with the function's closure as its parent.
 	// This allows the function to access variables from its defining scope.
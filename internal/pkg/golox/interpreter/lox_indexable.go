@@ -0,0 +1,10 @@
+package interpreter
+
+import "github.com/mejroslav/golox/internal/pkg/golox/token"
+
+// LoxIndexable represents any value that supports subscript access,
+// such as lists and maps.
+type LoxIndexable interface {
+	GetIndex(bracket token.Token, index any) (any, error)     // read the element at index
+	SetIndex(bracket token.Token, index any, value any) error // replace the element at index
+}
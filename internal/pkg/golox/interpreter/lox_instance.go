@@ -11,6 +11,7 @@ import (
 type LoxInstance struct {
 	Class  *LoxClass
 	Fields map[string]any
+	Native any // The Go value of an instance of a class bound to a Go type, see NativeClass
 }
 
 func NewLoxInstance(class *LoxClass) *LoxInstance {
@@ -20,8 +21,12 @@ func NewLoxInstance(class *LoxClass) *LoxInstance {
 	}
 }
 
-// String returns a string representation of the Lox instance.
+// String returns a string representation of the Lox instance, or the result
+// of the String method of its Go value if it has one.
 func (li *LoxInstance) String() string {
+	if stringer, ok := li.Native.(fmt.Stringer); ok {
+		return stringer.String()
+	}
 	return "<instance of " + li.Class.Name + ">"
 }
 
@@ -31,20 +36,60 @@ func (li *LoxInstance) Get(name token.Token) (any, error) {
 		return value, nil
 	}
 
-	if method, ok := li.FindMethod(name.Lexeme); ok {
-		return method.Bind(li), nil
+	if field, ok := li.nativeField(name.Lexeme); ok {
+		value, err := field.Get(li.Native)
+		if err != nil {
+			return nil, lox_error.NewRuntimeError(name, lox_error.CodeNativeError, fmt.Sprintf("Cannot read field '%s': %s.", name.Lexeme, err))
+		}
+		return value, nil
+	}
+
+	if method, ok := li.Class.bindMethod(name.Lexeme, li); ok {
+		return method, nil
 	}
 
-	err := lox_error.NewRuntimeError(name, fmt.Sprintf("Class '%s' has not defined property '%s'.", li.Class.Name, name.Lexeme))
+	err := undefinedProperty(name, fmt.Sprintf("Class '%s' has not defined property '%s'.", li.Class.Name, name.Lexeme), li.Properties())
 	return nil, err
 }
 
-// Set assigns a value to a property of the instance.
-func (li *LoxInstance) Set(name token.Token, value any) {
+// Set assigns a value to a property of the instance. It fails if the property
+// is a field of the Go value that cannot hold the value.
+func (li *LoxInstance) Set(name token.Token, value any) error {
+	if field, ok := li.nativeField(name.Lexeme); ok {
+		if err := field.Set(li.Native, value); err != nil {
+			return lox_error.NewRuntimeError(name, lox_error.CodeInvalidArgument, fmt.Sprintf("Invalid value for field '%s': %s.", name.Lexeme, err))
+		}
+		return nil
+	}
 	li.Fields[name.Lexeme] = value
+	return nil
+}
+
+// Properties returns the names of the fields and methods of the instance, including inherited methods.
+func (li *LoxInstance) Properties() []string {
+	names := li.Class.MethodNames()
+	for name := range li.Fields {
+		names = append(names, name)
+	}
+	if native := li.Class.nativeClass(); native != nil {
+		for name := range native.Fields {
+			names = append(names, name)
+		}
+	}
+	return names
 }
 
 // FindMethod looks up a method by name in the instance's class.
 func (li *LoxInstance) FindMethod(name string) (*LoxFunction, bool) {
 	return li.Class.GetMethod(name)
 }
+
+// nativeField looks up a field of the Go value of the instance.
+func (li *LoxInstance) nativeField(name string) (*NativeField, bool) {
+	native := li.Class.nativeClass()
+	if native == nil {
+		return nil, false
+	}
+	field, ok := native.Fields[name]
+	return field, ok
+}
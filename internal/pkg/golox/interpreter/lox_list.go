@@ -0,0 +1,156 @@
+package interpreter
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+	"github.com/mejroslav/golox/internal/pkg/golox/token"
+)
+
+// LoxList represents a list value in the Lox language.
+type LoxList struct {
+	Elements []any
+}
+
+func NewLoxList(elements []any) *LoxList {
+	return &LoxList{
+		Elements: elements,
+	}
+}
+
+// String returns a string representation of the list, e.g. [1, "two", nil].
+// A list that contains itself is printed as [...] inside itself.
+func (ll *LoxList) String() string {
+	return ll.format(map[any]bool{})
+}
+
+func (ll *LoxList) format(visiting map[any]bool) string {
+	if visiting[ll] {
+		return "[...]"
+	}
+	visiting[ll] = true
+	defer delete(visiting, ll)
+
+	parts := make([]string, len(ll.Elements))
+	for i, element := range ll.Elements {
+		parts[i] = reprVisiting(element, visiting)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// GetIndex returns the element at the given index.
+func (ll *LoxList) GetIndex(bracket token.Token, index any) (any, error) {
+	i, err := ll.checkIndex(bracket, index, len(ll.Elements)-1)
+	if err != nil {
+		return nil, err
+	}
+	return ll.Elements[i], nil
+}
+
+// SetIndex replaces the element at the given index.
+func (ll *LoxList) SetIndex(bracket token.Token, index any, value any) error {
+	i, err := ll.checkIndex(bracket, index, len(ll.Elements)-1)
+	if err != nil {
+		return err
+	}
+	ll.Elements[i] = value
+	return nil
+}
+
+// Properties returns the names of the built-in methods of the list.
+func (ll *LoxList) Properties() []string {
+	return []string{"len", "push", "pop", "insert", "slice", "contains"}
+}
+
+// Get retrieves a built-in method of the list.
+func (ll *LoxList) Get(name token.Token) (any, error) {
+	switch name.Lexeme {
+	case "len":
+		return NewNativeFunction("len", 0, func(interpreter *Interpreter, arguments []any) (any, error) {
+			return float64(len(ll.Elements)), nil
+		}), nil
+	case "push":
+		return NewNativeFunction("push", 1, func(interpreter *Interpreter, arguments []any) (any, error) {
+			ll.Elements = append(ll.Elements, arguments[0])
+			return nil, nil
+		}), nil
+	case "pop":
+		return NewNativeFunction("pop", 0, func(interpreter *Interpreter, arguments []any) (any, error) {
+			if len(ll.Elements) == 0 {
+				return nil, lox_error.NewRuntimeError(name, lox_error.CodeInvalidIndex, "Cannot pop from an empty list.")
+			}
+			last := ll.Elements[len(ll.Elements)-1]
+			ll.Elements = ll.Elements[:len(ll.Elements)-1]
+			return last, nil
+		}), nil
+	case "insert":
+		return NewNativeFunction("insert", 2, func(interpreter *Interpreter, arguments []any) (any, error) {
+			i, err := ll.checkIndex(name, arguments[0], len(ll.Elements))
+			if err != nil {
+				return nil, err
+			}
+			ll.Elements = append(ll.Elements, nil)
+			copy(ll.Elements[i+1:], ll.Elements[i:])
+			ll.Elements[i] = arguments[1]
+			return nil, nil
+		}), nil
+	case "slice":
+		return NewNativeFunction("slice", 2, func(interpreter *Interpreter, arguments []any) (any, error) {
+			start, err := ll.sliceBound(name, arguments[0])
+			if err != nil {
+				return nil, err
+			}
+			end, err := ll.sliceBound(name, arguments[1])
+			if err != nil {
+				return nil, err
+			}
+			end = max(start, end)
+			elements := make([]any, end-start)
+			copy(elements, ll.Elements[start:end])
+			return NewLoxList(elements), nil
+		}), nil
+	case "contains":
+		return NewNativeFunction("contains", 1, func(interpreter *Interpreter, arguments []any) (any, error) {
+			for _, element := range ll.Elements {
+				if isEqual(element, arguments[0]) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}), nil
+	}
+
+	return nil, undefinedProperty(name, fmt.Sprintf("List has no method '%s'.", name.Lexeme), ll.Properties())
+}
+
+// checkIndex checks that the index is an integer between 0 and upper (inclusive).
+func (ll *LoxList) checkIndex(at token.Token, index any, upper int) (int, error) {
+	number, ok := index.(float64)
+	if !ok || number != math.Trunc(number) {
+		return 0, lox_error.NewRuntimeError(at, lox_error.CodeInvalidIndex, "List index must be an integer.")
+	}
+	if number < 0 || number > float64(upper) {
+		err := lox_error.NewRuntimeError(at, lox_error.CodeInvalidIndex, fmt.Sprintf("List index %s out of range for list of length %d.", stringify(number), len(ll.Elements)))
+		if number < 0 {
+			err.Help = "indices start at 0 and cannot be negative; use xs[xs.len() - 1] for the last element"
+		}
+		return 0, err
+	}
+	return int(number), nil
+}
+
+// sliceBound converts a bound of 'slice' to an index between 0 and the length
+// of the list. Negative bounds count from the end, and bounds outside of the
+// list are clamped to it, so that slicing never fails for integer bounds.
+func (ll *LoxList) sliceBound(at token.Token, bound any) (int, error) {
+	number, ok := bound.(float64)
+	if !ok || number != math.Trunc(number) {
+		return 0, lox_error.NewRuntimeError(at, lox_error.CodeInvalidIndex, "Slice bounds must be integers.")
+	}
+	if number < 0 {
+		number += float64(len(ll.Elements))
+	}
+	return int(min(max(number, 0), float64(len(ll.Elements)))), nil
+}
@@ -0,0 +1,62 @@
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+)
+
+func TestLists(t *testing.T) {
+	runProgramTests(t, []programTest{
+		{
+			name:   "literal and indexing",
+			source: `var xs = [1, "two", nil]; print xs; print xs[1]; xs[2] = 3; print xs;`,
+			want:   "[1, \"two\", nil]\ntwo\n[1, \"two\", 3]\n",
+		},
+		{
+			name:   "methods",
+			source: `var xs = [1]; xs.push(2); xs.insert(0, 0); print xs; print xs.pop(); print xs.len(); print xs.contains(1);`,
+			want:   "[0, 1, 2]\n2\n2\ntrue\n",
+		},
+		{
+			name:   "list containing itself",
+			source: `var xs = [1]; xs.push(xs); print xs; print [xs, xs]; print "${xs}";`,
+			want:   "[1, [...]]\n[[1, [...]], [1, [...]]]\n[1, [...]]\n",
+		},
+		{
+			name:   "slice",
+			source: `print [1, 2, 3, 4].slice(1, 3);`,
+			want:   "[2, 3]\n",
+		},
+		{
+			name:   "slice counts negative bounds from the end",
+			source: `print [1, 2, 3, 4].slice(-2, -1);`,
+			want:   "[3]\n",
+		},
+		{
+			name:   "slice clamps the bounds",
+			source: `var xs = [1, 2, 3, 4]; print xs.slice(-10, 2); print xs.slice(2, 100); print xs.slice(3, 1);`,
+			want:   "[1, 2]\n[3, 4]\n[]\n",
+		},
+		{
+			name:   "fractional slice bound",
+			source: `[1, 2].slice(0.5, 1);`,
+			code:   lox_error.CodeInvalidIndex,
+		},
+		{
+			name:   "negative index",
+			source: `print [1, 2][-1];`,
+			code:   lox_error.CodeInvalidIndex,
+		},
+		{
+			name:   "index out of range",
+			source: `var xs = [1, 2]; xs[2] = 3;`,
+			code:   lox_error.CodeInvalidIndex,
+		},
+		{
+			name:   "pop from an empty list",
+			source: `[].pop();`,
+			code:   lox_error.CodeInvalidIndex,
+		},
+	})
+}
@@ -0,0 +1,154 @@
+package interpreter
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+	"github.com/mejroslav/golox/internal/pkg/golox/token"
+)
+
+// LoxMap represents a map (dictionary) value in the Lox language.
+//
+// Keys are compared with the same semantics as isEqual, and entries
+// keep their insertion order.
+type LoxMap struct {
+	keys    []any
+	entries map[any]any
+}
+
+func NewLoxMap() *LoxMap {
+	return &LoxMap{
+		keys:    []any{},
+		entries: make(map[any]any),
+	}
+}
+
+// String returns a string representation of the map, e.g. {"a": 1, 2: nil}.
+// A map that contains itself is printed as {...} inside itself.
+func (lm *LoxMap) String() string {
+	return lm.format(map[any]bool{})
+}
+
+func (lm *LoxMap) format(visiting map[any]bool) string {
+	if visiting[lm] {
+		return "{...}"
+	}
+	visiting[lm] = true
+	defer delete(visiting, lm)
+
+	parts := make([]string, len(lm.keys))
+	for i, key := range lm.keys {
+		parts[i] = reprVisiting(key, visiting) + ": " + reprVisiting(lm.entries[key], visiting)
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// Put inserts or replaces the value stored under key. The key must not be NaN,
+// see checkKey.
+func (lm *LoxMap) Put(key any, value any) {
+	if _, ok := lm.entries[key]; !ok {
+		lm.keys = append(lm.keys, key)
+	}
+	lm.entries[key] = value
+}
+
+// Has reports whether the map contains key.
+func (lm *LoxMap) Has(key any) bool {
+	_, ok := lm.entries[key]
+	return ok
+}
+
+// Remove deletes key from the map and returns its value, or nil if it was not present.
+func (lm *LoxMap) Remove(key any) any {
+	value, ok := lm.entries[key]
+	if !ok {
+		return nil
+	}
+	delete(lm.entries, key)
+	for i, k := range lm.keys {
+		if isEqual(k, key) {
+			lm.keys = append(lm.keys[:i], lm.keys[i+1:]...)
+			break
+		}
+	}
+	return value
+}
+
+// GetIndex returns the value stored under the given key.
+func (lm *LoxMap) GetIndex(bracket token.Token, key any) (any, error) {
+	if err := checkKey(bracket, key); err != nil {
+		return nil, err
+	}
+	value, ok := lm.entries[key]
+	if !ok {
+		return nil, lox_error.NewRuntimeError(bracket, lox_error.CodeInvalidIndex, fmt.Sprintf("Key %s not found in map.", repr(key)))
+	}
+	return value, nil
+}
+
+// SetIndex stores the value under the given key.
+func (lm *LoxMap) SetIndex(bracket token.Token, key any, value any) error {
+	if err := checkKey(bracket, key); err != nil {
+		return err
+	}
+	lm.Put(key, value)
+	return nil
+}
+
+// Properties returns the names of the built-in methods of the map.
+func (lm *LoxMap) Properties() []string {
+	return []string{"len", "keys", "values", "has", "remove"}
+}
+
+// Get retrieves a built-in method of the map.
+func (lm *LoxMap) Get(name token.Token) (any, error) {
+	switch name.Lexeme {
+	case "len":
+		return NewNativeFunction("len", 0, func(interpreter *Interpreter, arguments []any) (any, error) {
+			return float64(len(lm.keys)), nil
+		}), nil
+	case "keys":
+		return NewNativeFunction("keys", 0, func(interpreter *Interpreter, arguments []any) (any, error) {
+			keys := make([]any, len(lm.keys))
+			copy(keys, lm.keys)
+			return NewLoxList(keys), nil
+		}), nil
+	case "values":
+		return NewNativeFunction("values", 0, func(interpreter *Interpreter, arguments []any) (any, error) {
+			values := make([]any, len(lm.keys))
+			for i, key := range lm.keys {
+				values[i] = lm.entries[key]
+			}
+			return NewLoxList(values), nil
+		}), nil
+	case "has":
+		return NewNativeFunction("has", 1, func(interpreter *Interpreter, arguments []any) (any, error) {
+			if err := checkKey(name, arguments[0]); err != nil {
+				return nil, err
+			}
+			return lm.Has(arguments[0]), nil
+		}), nil
+	case "remove":
+		return NewNativeFunction("remove", 1, func(interpreter *Interpreter, arguments []any) (any, error) {
+			if err := checkKey(name, arguments[0]); err != nil {
+				return nil, err
+			}
+			return lm.Remove(arguments[0]), nil
+		}), nil
+	}
+
+	return nil, undefinedProperty(name, fmt.Sprintf("Map has no method '%s'.", name.Lexeme), lm.Properties())
+}
+
+// checkKey returns an error if the key is NaN. NaN is not equal to itself, so
+// an entry stored under it could never be found again.
+func checkKey(at token.Token, key any) error {
+	if number, ok := key.(float64); ok && math.IsNaN(number) {
+		err := lox_error.NewRuntimeError(at, lox_error.CodeInvalidIndex, "Map key cannot be NaN.")
+		err.Help = "NaN is not equal to itself, so a value stored under it could never be found"
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+)
+
+func TestMaps(t *testing.T) {
+	runProgramTests(t, []programTest{
+		{
+			name:   "literal and subscripts",
+			source: `var m = {"a": 1, 2: "b"}; m["c"] = 3; print m; print m[2];`,
+			want:   "{\"a\": 1, 2: \"b\", \"c\": 3}\nb\n",
+		},
+		{
+			name:   "methods",
+			source: `var m = {"a": 1, "b": 2}; print m.keys(); print m.values(); print m.has("a"); print m.remove("a"); print m.len();`,
+			want:   "[\"a\", \"b\"]\n[1, 2]\ntrue\n1\n1\n",
+		},
+		{
+			name:   "map containing itself",
+			source: `var m = {}; m["self"] = m; print m;`,
+			want:   "{\"self\": {...}}\n",
+		},
+		{
+			name:   "cycle through a list and a map",
+			source: `var m = {"xs": []}; m["xs"].push(m); print m; print m["xs"]; var k = [1]; k.push({k: k}); print k;`,
+			want:   "{\"xs\": [{...}]}\n[{\"xs\": [...]}]\n[1, {[...]: [...]}]\n",
+		},
+		{
+			name:   "block at the start of a statement",
+			source: `{ print "block"; }`,
+			want:   "block\n",
+		},
+		{
+			name:   "missing key",
+			source: `print {"a": 1}["b"];`,
+			code:   lox_error.CodeInvalidIndex,
+		},
+		{
+			name:   "NaN key in a literal",
+			source: `var m = {0/0: 1};`,
+			code:   lox_error.CodeInvalidIndex,
+		},
+		{
+			name:   "NaN key in an assignment",
+			source: `var m = {}; m[0/0] = 1;`,
+			code:   lox_error.CodeInvalidIndex,
+		},
+		{
+			name:   "NaN key in has",
+			source: `print {}.has(0/0);`,
+			code:   lox_error.CodeInvalidIndex,
+		},
+		{
+			name:   "NaN key can be caught",
+			source: `try { var m = {}; m[0/0] = 1; } catch (e) { print e.message; }`,
+			want:   "Map key cannot be NaN.\n",
+		},
+	})
+}
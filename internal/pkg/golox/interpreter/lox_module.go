@@ -0,0 +1,149 @@
+package interpreter
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/ast"
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+	"github.com/mejroslav/golox/internal/pkg/golox/token"
+)
+
+// ModuleLoader scans, parses and resolves the module at path and returns its statements.
+type ModuleLoader func(path string) ([]ast.Stmt, error)
+
+// LoxModule represents an imported module. Its top-level definitions
+// are accessible as properties, e.g. 'm.name'.
+type LoxModule struct {
+	Name        string
+	Path        string
+	Environment *Environment    // The global environment of the module
+	exports     map[string]bool // Names defined by the top-level statements of the module
+}
+
+func NewLoxModule(name string, path string, environment *Environment, statements []ast.Stmt) *LoxModule {
+	exports := make(map[string]bool)
+	for _, statement := range statements {
+		switch stmt := statement.(type) {
+		case *ast.Var:
+			exports[stmt.Name.Lexeme] = true
+		case *ast.Function:
+			exports[stmt.Name.Lexeme] = true
+		case *ast.Class:
+			exports[stmt.Name.Lexeme] = true
+		case *ast.Import:
+			if stmt.Alias != nil {
+				exports[stmt.Alias.Lexeme] = true
+			}
+			for _, name := range stmt.Names {
+				exports[name.Lexeme] = true
+			}
+		}
+	}
+
+	return &LoxModule{
+		Name:        name,
+		Path:        path,
+		Environment: environment,
+		exports:     exports,
+	}
+}
+
+// String returns a string representation of the module.
+func (lm *LoxModule) String() string {
+	return "<module " + lm.Name + ">"
+}
+
+// Properties returns the names of the top-level definitions of the module.
+func (lm *LoxModule) Properties() []string {
+	names := make([]string, 0, len(lm.exports))
+	for name := range lm.exports {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Get retrieves a top-level definition of the module.
+func (lm *LoxModule) Get(name token.Token) (any, error) {
+	if lm.exports[name.Lexeme] {
+		return lm.Environment.Get(&name)
+	}
+	return nil, undefinedProperty(name, fmt.Sprintf("Module '%s' has no member '%s'.", lm.Name, name.Lexeme), lm.Properties())
+}
+
+// importModule executes the module imported by stmt, unless it has already been executed.
+//
+// The module path is resolved relative to the file containing the import statement.
+func (i *Interpreter) importModule(stmt *ast.Import) (*LoxModule, error) {
+	relativePath, _ := stmt.Path.Literal.(string)
+	path := relativePath
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(stmt.Keyword.File), path)
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, lox_error.NewRuntimeError(*stmt.Path, lox_error.CodeImportFailed, fmt.Sprintf("Invalid module path '%s': %v", relativePath, err))
+	}
+
+	// The main file is cached before it has run, so look for cycles first.
+	for j, importing := range i.importStack {
+		if importing == path {
+			cycle := []string{}
+			for _, p := range i.importStack[j:] {
+				cycle = append(cycle, filepath.Base(p))
+			}
+			cycle = append(cycle, filepath.Base(path))
+			return nil, lox_error.NewRuntimeError(*stmt.Path, lox_error.CodeImportCycle, "Import cycle detected: "+strings.Join(cycle, " -> ")+".")
+		}
+	}
+
+	if module, ok := i.modules[path]; ok {
+		return module, nil
+	}
+
+	if i.moduleLoader == nil {
+		return nil, lox_error.NewRuntimeError(*stmt.Keyword, lox_error.CodeImportFailed, "Modules cannot be imported here.")
+	}
+
+	i.importStack = append(i.importStack, path)
+	defer func() {
+		i.importStack = i.importStack[:len(i.importStack)-1]
+	}()
+
+	statements, err := i.moduleLoader(path)
+	if err != nil {
+		return nil, lox_error.NewRuntimeError(*stmt.Path, lox_error.CodeImportFailed, fmt.Sprintf("Could not import '%s': %v", relativePath, err))
+	}
+
+	// Every module gets its own global environment with the built-in functions.
+	environment := NewEnvironment(nil)
+	defineNatives(environment)
+	_, err = i.executeBlock(statements, environment)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	module := NewLoxModule(name, path, environment, statements)
+	i.modules[path] = module
+	return module, nil
+}
+
+// EnterMainFile registers the main file of a program at path, whose statements
+// are run next, as a module. Importing it while the program runs is reported as
+// an import cycle instead of running it again, and importing it afterwards gives
+// its global variables. The returned function must be called once it has run.
+func (i *Interpreter) EnterMainFile(path string, statements []ast.Stmt) (exit func(), err error) {
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	i.modules[path] = NewLoxModule(name, path, i.globals, statements)
+	i.importStack = append(i.importStack, path)
+	return func() {
+		i.importStack = nil
+	}, nil
+}
@@ -0,0 +1,160 @@
+package interpreter_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/ast"
+	"github.com/mejroslav/golox/internal/pkg/golox/interpreter"
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+)
+
+// runModules runs the file main.lox of a set of files, given by name, which
+// are loaded from memory, and returns what it printed.
+func runModules(t *testing.T, files map[string]string) (string, error) {
+	t.Helper()
+	dir := t.TempDir()
+	interp := interpreter.NewInterpreter()
+	var stdout strings.Builder
+	interp.SetStdout(&stdout)
+	interp.SetModuleLoader(func(path string) ([]ast.Stmt, error) {
+		source, ok := files[filepath.Base(path)]
+		if !ok || filepath.Dir(path) != dir {
+			return nil, fmt.Errorf("no such file")
+		}
+		return load(t, interp, path, source), nil
+	})
+	path := filepath.Join(dir, "main.lox")
+	statements := load(t, interp, path, files["main.lox"])
+	exit, err := interp.EnterMainFile(path, statements)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer exit()
+	_, err = interp.Interpret(statements)
+	return stdout.String(), err
+}
+
+func TestModules(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    string
+		code    lox_error.Code
+		message string
+	}{
+		{
+			name: "import as",
+			files: map[string]string{
+				"main.lox": `import "math.lox" as m; print m.square(3); print m.pi;`,
+				"math.lox": `var pi = 3; fun square(x) { return x * x; }`,
+			},
+			want: "9\n3\n",
+		},
+		{
+			name: "from import",
+			files: map[string]string{
+				"main.lox": `from "math.lox" import square, pi; print square(pi);`,
+				"math.lox": `var pi = 3; fun square(x) { return x * x; }`,
+			},
+			want: "9\n",
+		},
+		{
+			name: "from and as are not reserved",
+			files: map[string]string{
+				"main.lox": `var as = 1; fun from(as) { return as + 1; } print from(as); import "m.lox" as from; from "m.lox" import as; print from.as + as;`,
+				"m.lox":    `var as = 40;`,
+			},
+			want: "2\n80\n",
+		},
+		{
+			name: "modules run once",
+			files: map[string]string{
+				"main.lox": `import "a.lox" as a; import "b.lox" as b; import "a.lox" as again; print a.count == again.count;`,
+				"a.lox":    `print "a runs"; var count = 1;`,
+				"b.lox":    `import "a.lox" as a; print "b runs";`,
+			},
+			want: "a runs\nb runs\ntrue\n",
+		},
+		{
+			name: "modules have their own globals",
+			files: map[string]string{
+				"main.lox": `var x = "main"; import "m.lox" as m; print m.x; print x;`,
+				"m.lox":    `var x = "module";`,
+			},
+			want: "module\nmain\n",
+		},
+		{
+			name: "import cycle",
+			files: map[string]string{
+				"main.lox": `import "a.lox" as a;`,
+				"a.lox":    `import "b.lox" as b;`,
+				"b.lox":    `import "a.lox" as a;`,
+			},
+			code:    lox_error.CodeImportCycle,
+			message: "Import cycle detected: a.lox -> b.lox -> a.lox.",
+		},
+		{
+			name: "module importing itself",
+			files: map[string]string{
+				"main.lox": `print "start"; import "a.lox" as a;`,
+				"a.lox":    `import "a.lox" as a;`,
+			},
+			want:    "start\n",
+			code:    lox_error.CodeImportCycle,
+			message: "Import cycle detected: a.lox -> a.lox.",
+		},
+		{
+			name: "import cycle through the main file",
+			files: map[string]string{
+				"main.lox": `print "main runs"; import "b.lox" as b;`,
+				"b.lox":    `import "main.lox" as main;`,
+			},
+			want:    "main runs\n",
+			code:    lox_error.CodeImportCycle,
+			message: "Import cycle detected: main.lox -> b.lox -> main.lox.",
+		},
+		{
+			name: "main file importing itself",
+			files: map[string]string{
+				"main.lox": `import "main.lox" as main;`,
+			},
+			code:    lox_error.CodeImportCycle,
+			message: "Import cycle detected: main.lox -> main.lox.",
+		},
+		{
+			name: "missing module",
+			files: map[string]string{
+				"main.lox": `import "missing.lox" as m;`,
+			},
+			code: lox_error.CodeImportFailed,
+		},
+		{
+			name: "missing name",
+			files: map[string]string{
+				"main.lox": `from "m.lox" import y;`,
+				"m.lox":    `var x = 1;`,
+			},
+			code: lox_error.CodeUndefinedProperty,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := runModules(t, test.files)
+			if code := errorCode(err); code != test.code {
+				t.Errorf("error %v, want code %q", err, test.code)
+			}
+			if test.message != "" {
+				if runtimeErr, ok := err.(lox_error.RuntimeError); !ok || runtimeErr.Message != test.message {
+					t.Errorf("error %v, want message %q", err, test.message)
+				}
+			}
+			if got != test.want {
+				t.Errorf("output %q, want %q", got, test.want)
+			}
+		})
+	}
+}
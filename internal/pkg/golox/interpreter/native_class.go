@@ -0,0 +1,43 @@
+package interpreter
+
+// NativeClass binds a type implemented in Go to a Lox class. Every instance of
+// the class, and of its subclasses declared in Lox, holds a value of the Go type
+// in LoxInstance.Native, whose fields and methods are properties of the instance.
+// Methods declared in Lox subclasses override the methods of the Go type.
+type NativeClass struct {
+	New     func() any               // Creates the Go value of a new instance, before 'init' runs
+	Fields  map[string]*NativeField  // The fields of the Go value, by name
+	Methods map[string]*NativeMethod // The methods of the Go value, by name, with 'init' if the class has a constructor
+}
+
+// NativeField is a field of the Go value of an instance, see NativeClass.
+type NativeField struct {
+	Get func(value any) (any, error)
+	Set func(value any, fieldValue any) error
+}
+
+// NativeMethod is a method implemented in Go, see NativeClass.
+type NativeMethod struct {
+	Name     string
+	arity    int
+	variadic bool
+	function func(interpreter *Interpreter, this *LoxInstance, arguments []any) (any, error)
+}
+
+func NewNativeMethod(name string, arity int, variadic bool, function func(interpreter *Interpreter, this *LoxInstance, arguments []any) (any, error)) *NativeMethod {
+	return &NativeMethod{
+		Name:     name,
+		arity:    arity,
+		variadic: variadic,
+		function: function,
+	}
+}
+
+// Bind returns the method as a native function called on the given instance.
+func (nm *NativeMethod) Bind(this *LoxInstance) *NativeFunction {
+	nf := NewNativeFunction(nm.Name, nm.arity, func(interpreter *Interpreter, arguments []any) (any, error) {
+		return nm.function(interpreter, this, arguments)
+	})
+	nf.variadic = nm.variadic
+	return nf
+}
@@ -0,0 +1,44 @@
+package interpreter
+
+// NativeFunction is a callable implemented in Go, such as a built-in method of a list.
+type NativeFunction struct {
+	Name     string
+	arity    int
+	variadic bool // Whether more than arity arguments are accepted
+	function func(interpreter *Interpreter, arguments []any) (any, error)
+}
+
+func NewNativeFunction(name string, arity int, function func(interpreter *Interpreter, arguments []any) (any, error)) *NativeFunction {
+	return &NativeFunction{
+		Name:     name,
+		arity:    arity,
+		function: function,
+	}
+}
+
+// NewVariadicNativeFunction returns a native function accepting at least minArity arguments.
+func NewVariadicNativeFunction(name string, minArity int, function func(interpreter *Interpreter, arguments []any) (any, error)) *NativeFunction {
+	nf := NewNativeFunction(name, minArity, function)
+	nf.variadic = true
+	return nf
+}
+
+// Arity returns the number of parameters the native function expects.
+func (nf *NativeFunction) Arity() int {
+	return nf.arity
+}
+
+// Variadic reports whether the native function accepts more arguments than its arity.
+func (nf *NativeFunction) Variadic() bool {
+	return nf.variadic
+}
+
+// Call executes the underlying Go function with the given arguments.
+func (nf *NativeFunction) Call(interpreter *Interpreter, arguments []any) (any, error) {
+	return nf.function(interpreter, arguments)
+}
+
+// String returns a string representation of the native function.
+func (nf *NativeFunction) String() string {
+	return "<native fn " + nf.Name + ">"
+}
@@ -0,0 +1,81 @@
+package interpreter
+
+import (
+	"reflect"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/token"
+)
+
+// Position returns the position of the innermost statement being executed that
+// has a token, or else the call site of the innermost call, or false if no
+// statement is being executed. It is meant for errors that occur outside of
+// the evaluation of an expression with a token, such as Go panics and exceeded
+// limits.
+func (i *Interpreter) Position() (token.Token, bool) {
+	for j := len(i.statements) - 1; j >= 0; j-- {
+		if t, ok := nodePosition(reflect.ValueOf(i.statements[j])); ok {
+			return t, true
+		}
+	}
+	for j := len(i.frames) - 1; j >= 0; j-- {
+		if call := i.frames[j].Call; call.Line > 0 {
+			return call, true
+		}
+	}
+	return token.Token{}, false
+}
+
+// CallPosition returns the position of the call of the native function being
+// executed, or of the statement being executed if the function was called from
+// Go. Native functions use it for their errors, and must call it before calling
+// back into Lox.
+func (i *Interpreter) CallPosition() token.Token {
+	if i.callSite != nil {
+		return *i.callSite
+	}
+	position, _ := i.Position()
+	return position
+}
+
+// Unwind returns the interpreter to the top level after a panic interrupted
+// the execution, so that it can run further statements in the global environment.
+func (i *Interpreter) Unwind() {
+	i.environment = i.globals
+	i.frames = nil
+	i.callSite = nil
+	i.importStack = nil
+	i.statements = nil
+}
+
+// nodePosition returns the first token of an AST node, searching its fields depth first.
+// It uses reflection to cover all node types, as it is only used to report internal errors.
+func nodePosition(value reflect.Value) (token.Token, bool) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return token.Token{}, false
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		if t, ok := value.Interface().(token.Token); ok {
+			return t, true
+		}
+		for field := 0; field < value.NumField(); field++ {
+			if !value.Type().Field(field).IsExported() {
+				continue
+			}
+			if t, ok := nodePosition(value.Field(field)); ok {
+				return t, true
+			}
+		}
+	case reflect.Slice:
+		for element := 0; element < value.Len(); element++ {
+			if t, ok := nodePosition(value.Index(element)); ok {
+				return t, true
+			}
+		}
+	}
+	return token.Token{}, false
+}
@@ -0,0 +1,33 @@
+package interpreter
+
+import (
+	"github.com/mejroslav/golox/internal/pkg/golox/ast"
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+	"github.com/mejroslav/golox/internal/pkg/golox/token"
+)
+
+// Suggest records names similar to a variable that the resolver could not
+// find in any local scope. They are offered if the variable turns out to be
+// undefined at runtime, together with the names visible at that point.
+func (i *Interpreter) Suggest(e ast.Expr, names []string) {
+	i.suggestions[e] = names
+}
+
+// withSuggestions adds a "did you mean" hint to an undefined variable error.
+func (i *Interpreter) withSuggestions(err error, expr ast.Expr, name string) error {
+	runtimeErr, ok := err.(lox_error.RuntimeError)
+	if !ok || runtimeErr.Code != lox_error.CodeUndefinedVariable {
+		return err
+	}
+	candidates := append(i.environment.Names(), i.suggestions[expr]...)
+	runtimeErr.Help = lox_error.DidYouMean(lox_error.Suggest(name, candidates))
+	return runtimeErr
+}
+
+// undefinedProperty returns the error for accessing a property that does not
+// exist, suggesting the most similar of the existing properties.
+func undefinedProperty(name token.Token, message string, properties []string) error {
+	err := lox_error.NewRuntimeError(name, lox_error.CodeUndefinedProperty, message)
+	err.Help = lox_error.DidYouMean(lox_error.Suggest(name.Lexeme, properties))
+	return err
+}
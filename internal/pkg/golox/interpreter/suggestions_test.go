@@ -0,0 +1,83 @@
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+)
+
+func TestSuggestions(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		code   lox_error.Code
+		help   string
+	}{
+		{
+			name:   "global variable",
+			source: `var counter = 1; print countr;`,
+			code:   lox_error.CodeUndefinedVariable,
+			help:   "did you mean 'counter'?",
+		},
+		{
+			name:   "local variable",
+			source: `{ var local = 1; print locl; }`,
+			code:   lox_error.CodeUndefinedVariable,
+			help:   "did you mean 'local'?",
+		},
+		{
+			name:   "variable of an enclosing function",
+			source: `fun outer() { var total = 1; fun inner() { return totl; } return inner(); } outer();`,
+			code:   lox_error.CodeUndefinedVariable,
+			help:   "did you mean 'total'?",
+		},
+		{
+			name:   "function",
+			source: `fun foo() {} fo();`,
+			code:   lox_error.CodeUndefinedVariable,
+			help:   "did you mean 'foo'?",
+		},
+		{
+			name:   "nothing similar",
+			source: `print zzzz;`,
+			code:   lox_error.CodeUndefinedVariable,
+		},
+		{
+			name:   "field",
+			source: `class A { init() { this.value = 1; } } print A().valeu;`,
+			code:   lox_error.CodeUndefinedProperty,
+			help:   "did you mean 'value'?",
+		},
+		{
+			name:   "method",
+			source: `class A { method() {} } A().methd();`,
+			code:   lox_error.CodeUndefinedProperty,
+			help:   "did you mean 'method'?",
+		},
+		{
+			name:   "inherited method",
+			source: `class A { method() {} } class B < A {} B().methd();`,
+			code:   lox_error.CodeUndefinedProperty,
+			help:   "did you mean 'method'?",
+		},
+		{
+			name:   "list method",
+			source: `[].psh(1);`,
+			code:   lox_error.CodeUndefinedProperty,
+			help:   "did you mean 'push'?",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := run(t, test.source)
+			runtimeErr, ok := err.(lox_error.RuntimeError)
+			if !ok || runtimeErr.Code != test.code {
+				t.Fatalf("error %v, want code %q", err, test.code)
+			}
+			if runtimeErr.Help != test.help {
+				t.Errorf("help %q, want %q", runtimeErr.Help, test.help)
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package lox_error
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Code is a stable identifier of a kind of error, such as "E0101" for an undefined variable.
+// Codes never change their meaning, so they can be searched for and referred to in documentation.
+//
+// Codes are grouped by their hundreds: E00xx for scanner errors, E01xx for names,
+// E02xx for syntax, E03xx for misplaced statements, E04xx for runtime type errors,
+// E05xx for modules and E09xx for failures of the interpreter itself. Codes of
+// warnings start with W instead of E.
+type Code string
+
+const (
+	CodeUnexpectedCharacter Code = "E0001"
+	CodeUnterminatedString  Code = "E0002"
+	CodeInvalidEscape       Code = "E0003"
+	CodeInvalidNumber       Code = "E0004"
+
+	CodeUndefinedVariable Code = "E0101"
+	CodeUndefinedProperty Code = "E0102"
+	CodeAlreadyDeclared   Code = "E0103"
+	CodeOwnInitializer    Code = "E0104"
+
+	CodeExpectedToken           Code = "E0201"
+	CodeExpectedExpression      Code = "E0202"
+	CodeInvalidAssignmentTarget Code = "E0203"
+	CodeTooManyArguments        Code = "E0204"
+
+	CodeTopLevelReturn         Code = "E0301"
+	CodeInitializerReturn      Code = "E0302"
+	CodeLoopControlOutsideLoop Code = "E0303"
+	CodeThisOutsideClass       Code = "E0304"
+	CodeInvalidSuper           Code = "E0305"
+	CodeSelfInheritance        Code = "E0306"
+
+	CodeInvalidOperand    Code = "E0401"
+	CodeNotCallable       Code = "E0402"
+	CodeArityMismatch     Code = "E0403"
+	CodeNotAnInstance     Code = "E0404"
+	CodeNotIndexable      Code = "E0405"
+	CodeInvalidIndex      Code = "E0406"
+	CodeInvalidSuperclass Code = "E0407"
+	CodeUncaughtException Code = "E0408"
+	CodeStackOverflow     Code = "E0409"
+	CodeLimitExceeded     Code = "E0410"
+	CodeInvalidArgument   Code = "E0411"
+	CodeNativeError       Code = "E0412"
+
+	CodeImportFailed Code = "E0501"
+	CodeImportCycle  Code = "E0502"
+
+	CodeInternalError Code = "E0901"
+
+	CodeUnusedVariable         Code = "W0001"
+	CodeUnreadAssignment       Code = "W0002"
+	CodeUnreachableCode        Code = "W0003"
+	CodeShadowedVariable       Code = "W0004"
+	CodeSuperWithoutSuperclass Code = "W0005"
+)
+
+// Explanation documents an error code for 'golox explain'.
+type Explanation struct {
+	Title       string
+	Description string
+	Wrong       string // Lox code that causes the error
+	Fixed       string // The same code corrected
+}
+
+// Catalog documents every error code.
+var Catalog = map[Code]Explanation{
+	CodeUnexpectedCharacter: {
+		Title:       "Unexpected character",
+		Description: "The source contains a character that does not start any token of Lox, for example '@' or '#'.",
+		Wrong:       "var total = 10 # 3;",
+		Fixed:       "var total = 10 % 3;",
+	},
+	CodeUnterminatedString: {
+		Title:       "Unterminated string",
+		Description: "A string literal, or an interpolation \"${...}\" inside it, is not closed before the end of the file.",
+		Wrong:       "var name = \"Lox\";\nprint \"Hello, ${name;",
+		Fixed:       "var name = \"Lox\";\nprint \"Hello, ${name}\";",
+	},
+	CodeInvalidEscape: {
+		Title:       "Invalid escape sequence",
+		Description: "A backslash in a string must be followed by one of n, t, r, 0, \\, \", $, or a unicode escape \\uXXXX or \\u{X...} naming a valid code point.",
+		Wrong:       "print \"C:\\Users\";",
+		Fixed:       "print \"C:\\\\Users\";",
+	},
+	CodeInvalidNumber: {
+		Title:       "Invalid number literal",
+		Description: "A number literal is malformed, for example a hexadecimal prefix without digits or an exponent without a value.",
+		Wrong:       "var mask = 0x;",
+		Fixed:       "var mask = 0xFF;",
+	},
+	CodeUndefinedVariable: {
+		Title:       "Undefined variable",
+		Description: "A variable is read or assigned, but no variable with that name has been declared in any enclosing scope or globally. Check the spelling and make sure the declaration runs before the use.",
+		Wrong:       "var count = 1;\nprint coutn;",
+		Fixed:       "var count = 1;\nprint count;",
+	},
+	CodeUndefinedProperty: {
+		Title:       "Undefined property",
+		Description: "An object does not have the accessed field or method. Instances have the fields assigned to them and the methods of their class and its superclasses; lists, maps, errors and modules have a fixed set of properties.",
+		Wrong:       "class Point { init(x) { this.x = x; } }\nprint Point(1).y;",
+		Fixed:       "class Point { init(x) { this.x = x; } }\nprint Point(1).x;",
+	},
+	CodeAlreadyDeclared: {
+		Title:       "Variable already declared",
+		Description: "A local scope declares the same name twice. Global variables may be redeclared, local ones may not.",
+		Wrong:       "{\n  var a = 1;\n  var a = 2;\n}",
+		Fixed:       "{\n  var a = 1;\n  a = 2;\n}",
+	},
+	CodeOwnInitializer: {
+		Title:       "Local variable read in its own initializer",
+		Description: "The initializer of a local variable refers to the variable being declared, which does not have a value yet.",
+		Wrong:       "var a = 1;\n{\n  var a = a + 1;\n}",
+		Fixed:       "var a = 1;\n{\n  var b = a + 1;\n}",
+	},
+	CodeExpectedToken: {
+		Title:       "Expected token",
+		Description: "The parser needed a particular token, such as ';', ')' or a name, but found something else. The error points at the token found instead.",
+		Wrong:       "var x = 1\nprint x;",
+		Fixed:       "var x = 1;\nprint x;",
+	},
+	CodeExpectedExpression: {
+		Title:       "Expected expression",
+		Description: "The parser needed an expression, such as a literal, a variable or a call, but found a token that cannot start one.",
+		Wrong:       "var x = ;",
+		Fixed:       "var x = nil;",
+	},
+	CodeInvalidAssignmentTarget: {
+		Title:       "Invalid assignment target",
+		Description: "Only variables, fields and list or map elements can be assigned, incremented or decremented.",
+		Wrong:       "var a = 1;\na + 1 = 3;",
+		Fixed:       "var a = 1;\na = 3 - 1;",
+	},
+	CodeTooManyArguments: {
+		Title:       "Too many arguments or parameters",
+		Description: "A function can have at most 255 parameters, and a call can pass at most 255 arguments. Pass a list or an instance instead.",
+		Wrong:       "// A function with 256 parameters p1 to p256:\nfun f(p1, p2, p3, ..., p256) {}",
+		Fixed:       "fun f(arguments) {}",
+	},
+	CodeTopLevelReturn: {
+		Title:       "Return outside of a function",
+		Description: "A 'return' statement can only appear inside a function or method body.",
+		Wrong:       "return 1;",
+		Fixed:       "fun one() {\n  return 1;\n}",
+	},
+	CodeInitializerReturn: {
+		Title:       "Return of a value from an initializer",
+		Description: "An 'init' method always returns the new instance. It may use 'return;' to stop early, but not return another value.",
+		Wrong:       "class A {\n  init() { return 1; }\n}",
+		Fixed:       "class A {\n  init() { return; }\n}",
+	},
+	CodeLoopControlOutsideLoop: {
+		Title:       "Break or continue outside of a loop",
+		Description: "'break' and 'continue' can only appear inside the body of a 'while' or 'for' loop, and not inside a function nested in the loop.",
+		Wrong:       "var done = true;\nif (done) break;",
+		Fixed:       "var done = true;\nwhile (true) {\n  if (done) break;\n}",
+	},
+	CodeThisOutsideClass: {
+		Title:       "This outside of a class",
+		Description: "'this' refers to the instance a method was called on, so it can only be used inside methods.",
+		Wrong:       "fun name() { return this.name; }",
+		Fixed:       "class Person {\n  name() { return this.name; }\n}",
+	},
+	CodeInvalidSuper: {
+		Title:       "Invalid use of super",
+		Description: "'super' can only be used inside methods of a class that inherits from another class.",
+		Wrong:       "class A {\n  f() { return super.f(); }\n}",
+		Fixed:       "class Base { f() { return 1; } }\nclass A < Base {\n  f() { return super.f(); }\n}",
+	},
+	CodeSelfInheritance: {
+		Title:       "Class inherits from itself",
+		Description: "A class cannot be its own superclass.",
+		Wrong:       "class A < A {}",
+		Fixed:       "class Base {}\nclass A < Base {}",
+	},
+	CodeInvalidOperand: {
+		Title:       "Invalid operand",
+		Description: "An operator was applied to values of the wrong type, for example subtracting strings, or a bitwise operator to numbers that are not integers.",
+		Wrong:       "print \"10\" - 1;",
+		Fixed:       "print 10 - 1;",
+	},
+	CodeNotCallable: {
+		Title:       "Value is not callable",
+		Description: "Only functions, methods and classes can be called.",
+		Wrong:       "var greeting = \"hi\";\ngreeting();",
+		Fixed:       "fun greeting() { print \"hi\"; }\ngreeting();",
+	},
+	CodeArityMismatch: {
+		Title:       "Wrong number of arguments",
+		Description: "A function was called with a different number of arguments than it has parameters. Calling a class passes the arguments to its 'init' method.",
+		Wrong:       "fun add(a, b) { return a + b; }\nadd(1);",
+		Fixed:       "fun add(a, b) { return a + b; }\nadd(1, 2);",
+	},
+	CodeNotAnInstance: {
+		Title:       "Value has no properties",
+		Description: "Properties can only be read from instances, lists, maps, errors and modules, and fields can only be set on instances.",
+		Wrong:       "var n = 1;\nn.value = 2;",
+		Fixed:       "class Box {}\nvar n = Box();\nn.value = 2;",
+	},
+	CodeNotIndexable: {
+		Title:       "Value cannot be indexed",
+		Description: "Only lists and maps support the subscript operator '[]'.",
+		Wrong:       "var s = \"abc\";\nprint s[0];",
+		Fixed:       "var s = [\"a\", \"b\", \"c\"];\nprint s[0];",
+	},
+	CodeInvalidIndex: {
+		Title:       "Invalid index or key",
+		Description: "A list index must be an integer within the bounds of the list, a map key must be present in the map when it is read, and NaN cannot be a map key.",
+		Wrong:       "var xs = [1, 2, 3];\nprint xs[3];",
+		Fixed:       "var xs = [1, 2, 3];\nprint xs[2];",
+	},
+	CodeInvalidSuperclass: {
+		Title:       "Superclass is not a class",
+		Description: "The name after '<' in a class declaration must refer to a class.",
+		Wrong:       "var Base = 1;\nclass A < Base {}",
+		Fixed:       "class Base {}\nclass A < Base {}",
+	},
+	CodeUncaughtException: {
+		Title:       "Uncaught exception",
+		Description: "A value was thrown with 'throw' and no enclosing 'try' statement caught it.",
+		Wrong:       "throw Error(\"failed\");",
+		Fixed:       "try {\n  throw Error(\"failed\");\n} catch (e) {\n  print e.message;\n}",
+	},
+	CodeStackOverflow: {
+		Title:       "Stack overflow",
+		Description: "Calls were nested deeper than the limit of the interpreter, usually because a recursive function never reaches its base case. The limit is 10000 nested calls by default and can be changed with --max-call-depth. The error can be caught with 'try'.",
+		Wrong:       "fun countdown(n) {\n  print n;\n  countdown(n - 1);\n}\ncountdown(3);",
+		Fixed:       "fun countdown(n) {\n  if (n < 0) return;\n  print n;\n  countdown(n - 1);\n}\ncountdown(3);",
+	},
+	CodeLimitExceeded: {
+		Title:       "Execution limit exceeded",
+		Description: "The program ran longer than the time limit, executed more statements and expressions than the step limit, or created more instances, lists, maps and strings than the allocation limit, or its execution was canceled. The limits are set with --timeout, --max-steps and --max-allocations, and are disabled by default. The error cannot be caught with 'try'.",
+		Wrong:       "// golox --max-steps 1000\nwhile (true) {}",
+		Fixed:       "var i = 0;\nwhile (i < 10) i += 1;",
+	},
+	CodeInvalidArgument: {
+		Title:       "Invalid argument",
+		Description: "A function implemented in Go was called with an argument that cannot be converted to the type of its parameter, or a field of a Go value was assigned a value that does not fit its type, for example a string for a number, or a number with a fractional part for an integer.",
+		Wrong:       "// a Go function registered as repeat(s string, n int)\nprint repeat(\"ab\", 1.5);",
+		Fixed:       "// a Go function registered as repeat(s string, n int)\nprint repeat(\"ab\", 2);",
+	},
+	CodeNativeError: {
+		Title:       "Native function failed",
+		Description: "A function implemented in Go failed, for example 'input()' could not read from the standard input, or a Go function registered by the program embedding golox returned an error, whose message becomes the message of the Lox error. The error can be caught with 'try'.",
+		Wrong:       "// a Go function registered as readFile(path string) (string, error)\nprint readFile(\"missing.txt\");",
+		Fixed:       "// a Go function registered as readFile(path string) (string, error)\ntry {\n  print readFile(\"missing.txt\");\n} catch (e) {\n  print \"cannot read: ${e.message}\";\n}",
+	},
+	CodeImportFailed: {
+		Title:       "Module cannot be imported",
+		Description: "The imported file does not exist, could not be read, or contains errors. Module paths are relative to the importing file.",
+		Wrong:       "import \"missing.lox\" as missing;",
+		Fixed:       "import \"modules/geometry.lox\" as geometry;",
+	},
+	CodeImportCycle: {
+		Title:       "Import cycle",
+		Description: "A module imports itself, directly or through other modules. Move the shared definitions into a separate module imported by both.",
+		Wrong:       "// a.lox\nimport \"b.lox\" as b;\n// b.lox\nimport \"a.lox\" as a;",
+		Fixed:       "// a.lox\nimport \"shared.lox\" as shared;\n// b.lox\nimport \"shared.lox\" as shared;",
+	},
+	CodeUnusedVariable: {
+		Title:       "Unused local variable",
+		Description: "A local variable, parameter, function or class is declared but never used. Remove it, or start its name with '_' if it is unused on purpose, e.g. a parameter required by the caller. Suppressed by '// lox:ignore unused'.",
+		Wrong:       "fun area(width, height) {\n  var unit = \"cm\";\n  return width * width;\n}",
+		Fixed:       "fun area(width, height) {\n  return width * height;\n}",
+	},
+	CodeUnreadAssignment: {
+		Title:       "Assigned value is never read",
+		Description: "A local variable is assigned, but its value is never read afterwards, so the assignments have no effect. Suppressed by '// lox:ignore unread'.",
+		Wrong:       "fun greet(name) {\n  var message = \"Hello\";\n  message = \"Hello, \" + name;\n  print \"Hello\";\n}",
+		Fixed:       "fun greet(name) {\n  var message = \"Hello, \" + name;\n  print message;\n}",
+	},
+	CodeUnreachableCode: {
+		Title:       "Unreachable code",
+		Description: "Statements follow a 'return', 'break', 'continue' or 'throw' in the same block, so they never run. Suppressed by '// lox:ignore unreachable'.",
+		Wrong:       "fun sign(x) {\n  return x < 0 ? -1 : 1;\n  print \"done\";\n}",
+		Fixed:       "fun sign(x) {\n  print \"done\";\n  return x < 0 ? -1 : 1;\n}",
+	},
+	CodeSuperWithoutSuperclass: {
+		Title:       "'super' in a class without a superclass",
+		Description: "A method uses 'super', but its class does not inherit from another class. The program does not run, as this is also the error E0305; the warning lists such methods together with the other warnings of 'golox check'. Add the missing superclass or call a method of 'this' instead. Suppressed by '// lox:ignore super'.",
+		Wrong:       "class Greeter {\n  greet() { return super.greet(); }\n}",
+		Fixed:       "class Base {\n  greet() { return \"hi\"; }\n}\nclass Greeter < Base {\n  greet() { return super.greet(); }\n}",
+	},
+	CodeInternalError: {
+		Title:       "Internal error",
+		Description: "The interpreter failed with a Go panic, because of a bug in golox or in a Go function called by it. The error points at the statement being executed. Please report it together with the code that caused it.",
+		Wrong:       "// a Go function registered as repeat(s string, n int), which panics for n < 0\nprint repeat(\"ab\", -1);",
+		Fixed:       "// a Go function registered as repeat(s string, n int), which panics for n < 0\nprint repeat(\"ab\", 2);",
+	},
+	CodeShadowedVariable: {
+		Title:       "Variable shadows another variable",
+		Description: "A local variable has the same name as a variable of an enclosing scope or a global variable, which then cannot be accessed in its scope. Suppressed by '// lox:ignore shadow'.",
+		Wrong:       "var count = 0;\nfun increment() {\n  var count = 1;\n  return count;\n}",
+		Fixed:       "var count = 0;\nfun increment() {\n  var step = 1;\n  return count + step;\n}",
+	},
+}
+
+// Codes returns all documented error codes in ascending order.
+func Codes() []Code {
+	codes := make([]Code, 0, len(Catalog))
+	for code := range Catalog {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes
+}
+
+// Explain returns the long-form explanation of a code, with an example of
+// code causing the error and its corrected version.
+func Explain(code Code) (string, bool) {
+	explanation, ok := Catalog[Code(strings.ToUpper(string(code)))]
+	if !ok {
+		return "", false
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%s: %s\n\n%s\n\n", strings.ToUpper(string(code)), explanation.Title, explanation.Description)
+	fmt.Fprintf(&builder, "Erroneous code example:\n\n%s\n\n", indent(explanation.Wrong))
+	fmt.Fprintf(&builder, "Corrected code:\n\n%s\n", indent(explanation.Fixed))
+	return builder.String(), true
+}
+
+func indent(code string) string {
+	return "    " + strings.ReplaceAll(code, "\n", "\n    ")
+}
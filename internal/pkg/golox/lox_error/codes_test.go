@@ -0,0 +1,106 @@
+package lox_error
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// declaredCodes returns the values of all constants of type Code declared in codes.go.
+func declaredCodes(t *testing.T) []Code {
+	t.Helper()
+	file, err := parser.ParseFile(token.NewFileSet(), "codes.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codes := []Code{}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			value := spec.(*ast.ValueSpec)
+			if ident, ok := value.Type.(*ast.Ident); !ok || ident.Name != "Code" {
+				continue
+			}
+			for _, v := range value.Values {
+				code, err := strconv.Unquote(v.(*ast.BasicLit).Value)
+				if err != nil {
+					t.Fatal(err)
+				}
+				codes = append(codes, Code(code))
+			}
+		}
+	}
+	return codes
+}
+
+func TestEveryCodeIsDocumented(t *testing.T) {
+	codes := declaredCodes(t)
+	if len(codes) == 0 {
+		t.Fatal("no codes found in codes.go")
+	}
+
+	seen := map[Code]bool{}
+	for _, code := range codes {
+		if seen[code] {
+			t.Errorf("code %s is declared twice", code)
+		}
+		seen[code] = true
+
+		explanation, ok := Catalog[code]
+		if !ok {
+			t.Errorf("code %s has no catalog entry", code)
+			continue
+		}
+		if explanation.Title == "" || explanation.Description == "" || explanation.Wrong == "" || explanation.Fixed == "" {
+			t.Errorf("catalog entry of %s is incomplete: %+v", code, explanation)
+		}
+		if explanation.Wrong == explanation.Fixed {
+			t.Errorf("catalog entry of %s has the same wrong and fixed code", code)
+		}
+	}
+
+	if len(Catalog) != len(codes) {
+		t.Errorf("catalog has %d entries, but %d codes are declared", len(Catalog), len(codes))
+	}
+}
+
+func TestExplain(t *testing.T) {
+	for _, code := range declaredCodes(t) {
+		for _, query := range []Code{code, Code(strings.ToLower(string(code)))} {
+			text, ok := Explain(query)
+			if !ok {
+				t.Errorf("Explain(%q) found no explanation", query)
+				continue
+			}
+			if !strings.HasPrefix(text, string(code)+": "+Catalog[code].Title) {
+				t.Errorf("Explain(%q) starts with %q", query, strings.SplitN(text, "\n", 2)[0])
+			}
+			if !strings.Contains(text, "Erroneous code example:") || !strings.Contains(text, "Corrected code:") {
+				t.Errorf("Explain(%q) has no examples:\n%s", query, text)
+			}
+		}
+	}
+
+	if _, ok := Explain("E9999"); ok {
+		t.Error("Explain(E9999) found an explanation")
+	}
+}
+
+func TestCodesAreSorted(t *testing.T) {
+	codes := Codes()
+	if len(codes) != len(Catalog) {
+		t.Fatalf("Codes() returned %d codes, catalog has %d", len(codes), len(Catalog))
+	}
+	for i := 1; i < len(codes); i++ {
+		if codes[i-1] >= codes[i] {
+			t.Errorf("Codes() is not sorted: %s before %s", codes[i-1], codes[i])
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package lox_error
+
+import (
+	"strings"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/token"
+)
+
+// Severity tells how serious a diagnostic is.
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "WARNING"
+	default:
+		return "ERROR"
+	}
+}
+
+// Phase names the stage of the interpreter that produced a diagnostic.
+type Phase string
+
+const (
+	PhaseScanner  Phase = "scanner"
+	PhaseParser   Phase = "parser"
+	PhaseResolver Phase = "resolver"
+	PhaseRuntime  Phase = "runtime"
+)
+
+// Span is a range of source code. Lines and columns start at 1 and the end column is inclusive.
+type Span struct {
+	File        string
+	StartLine   int
+	StartColumn int
+	EndLine     int
+	EndColumn   int
+}
+
+// TokenSpan returns the span covered by the lexeme of a token.
+//
+// The position of a token is the position of its last character,
+// so the start is computed back from the length of the lexeme.
+func TokenSpan(t token.Token) Span {
+	lines := strings.Split(t.Lexeme, "\n")
+	startColumn := t.Column - len(lines[len(lines)-1]) + 1
+	if len(lines) > 1 {
+		startColumn = 1
+	}
+	if t.Lexeme == "" {
+		// Tokens without text, like EOF, point just after the last character.
+		startColumn = t.Column + 1
+	}
+	return Span{
+		File:        t.File,
+		StartLine:   t.Line - len(lines) + 1,
+		StartColumn: max(startColumn, 1),
+		EndLine:     t.Line,
+		EndColumn:   max(t.Column, startColumn),
+	}
+}
+
+// Label marks a secondary span related to a diagnostic, such as a previous declaration.
+type Label struct {
+	Span    Span
+	Message string
+}
+
+// Diagnostic is an error or warning in the form common to all phases of the interpreter.
+type Diagnostic struct {
+	Severity Severity
+	Phase    Phase
+	Code     Code // Identifies the kind of problem, if it has been assigned one
+	Message  string
+	Span     Span     // The primary location of the problem
+	Labels   []Label  // Other locations related to the problem
+	Notes    []string // Additional context
+	Help     string   // A suggestion how to fix the problem, if there is one
+	Trace    []Frame  // The call stack of a runtime error, outermost call first
+}
+
+// Diagnosable is implemented by errors that can be described by a Diagnostic.
+type Diagnosable interface {
+	Diagnostic() Diagnostic
+}
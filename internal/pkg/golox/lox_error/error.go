@@ -8,47 +8,198 @@ import (
 
 // ScannerError reports an error encountered during scanning
 type ScannerError struct {
-	File    string
-	Line    int
-	Column  int
-	Context string
-	Message string
+	File      string
+	Line      int // The position of the first character of the invalid text
+	Column    int
+	EndLine   int // The position of the last character, or 0 for a single character
+	EndColumn int
+	Code      Code
+	Message   string
 }
 
 func (s ScannerError) Error() string {
-	return fmt.Sprintf("SCANNER ERROR [%s:%d:%d] %s\n%s\n", s.File, s.Line, s.Column, s.Message, s.Context)
+	return fmt.Sprintf("SCANNER ERROR[%s] [%s:%d:%d] %s\n", s.Code, s.File, s.Line, s.Column, s.Message)
+}
+
+// Diagnostic describes the error, pointing at the text where scanning failed.
+func (s ScannerError) Diagnostic() Diagnostic {
+	column := max(s.Column, 1)
+	endLine, endColumn := s.Line, column
+	if s.EndLine > 0 {
+		endLine, endColumn = s.EndLine, max(s.EndColumn, 1)
+	}
+	return Diagnostic{
+		Severity: SeverityError,
+		Phase:    PhaseScanner,
+		Code:     s.Code,
+		Message:  s.Message,
+		Span:     Span{File: s.File, StartLine: s.Line, StartColumn: column, EndLine: endLine, EndColumn: endColumn},
+	}
 }
 
 type ParserError struct {
 	Token   token.Token
+	Code    Code
 	Message string
 }
 
 // ParserError reports an error encountered during parsing
 func (p ParserError) Error() string {
 	if p.Token.Type == token.EOF {
-		return parserErrorMsg(p.Token.File, p.Token.Line, p.Token.Column, "at end", p.Message)
+		return parserErrorMsg(p.Token.File, p.Token.Line, p.Token.Column, p.Code, "at end", p.Message)
 	} else {
-		return parserErrorMsg(p.Token.File, p.Token.Line, p.Token.Column, "at '"+p.Token.Lexeme+"'", p.Message)
+		return parserErrorMsg(p.Token.File, p.Token.Line, p.Token.Column, p.Code, "at '"+p.Token.Lexeme+"'", p.Message)
 	}
 }
 
-func parserErrorMsg(file string, line int, column int, where string, message string) string {
-	return fmt.Sprintf("PARSER ERROR [%s:%d:%d] %s: %s\n", file, line, column, where, message)
+// Diagnostic describes the error, pointing at the token where parsing failed.
+func (p ParserError) Diagnostic() Diagnostic {
+	return Diagnostic{
+		Severity: SeverityError,
+		Phase:    PhaseParser,
+		Code:     p.Code,
+		Message:  p.Message,
+		Span:     TokenSpan(p.Token),
+	}
+}
+
+func parserErrorMsg(file string, line int, column int, code Code, where string, message string) string {
+	return fmt.Sprintf("PARSER ERROR[%s] [%s:%d:%d] %s: %s\n", code, file, line, column, where, message)
+}
+
+// ResolverError reports an error found by the static analysis of the resolver
+type ResolverError struct {
+	Token   token.Token
+	Code    Code
+	Message string
+}
+
+func NewResolverError(token token.Token, code Code, message string) ResolverError {
+	return ResolverError{
+		Token:   token,
+		Code:    code,
+		Message: message,
+	}
+}
+
+func (r ResolverError) Error() string {
+	return fmt.Sprintf("RESOLVER ERROR[%s] [%s:%d:%d] %s\n", r.Code, r.Token.File, r.Token.Line, r.Token.Column, r.Message)
+}
+
+// Diagnostic describes the error, pointing at the offending token.
+func (r ResolverError) Diagnostic() Diagnostic {
+	return Diagnostic{
+		Severity: SeverityError,
+		Phase:    PhaseResolver,
+		Code:     r.Code,
+		Message:  r.Message,
+		Span:     TokenSpan(r.Token),
+	}
 }
 
 type RuntimeError struct {
 	Token   token.Token
+	Code    Code
 	Message string
+	Help    string  // A hint how to fix the error, e.g. a similar name
+	Trace   []Frame // The call stack when the error occurred, outermost call first
 }
 
-func NewRuntimeError(token token.Token, message string) RuntimeError {
+func NewRuntimeError(token token.Token, code Code, message string) RuntimeError {
 	return RuntimeError{
 		Token:   token,
+		Code:    code,
 		Message: message,
 	}
 }
 
 func (r RuntimeError) Error() string {
-	return fmt.Sprintf("RUNTIME ERROR [%s:%d:%d] %s\n", r.Token.File, r.Token.Line, r.Token.Column, r.Message)
+	return fmt.Sprintf("RUNTIME ERROR[%s] [%s:%d:%d] %s\n", r.Code, r.Token.File, r.Token.Line, r.Token.Column, r.Message)
+}
+
+// Diagnostic describes the error, pointing at the token where it occurred.
+func (r RuntimeError) Diagnostic() Diagnostic {
+	return Diagnostic{
+		Severity: SeverityError,
+		Phase:    PhaseRuntime,
+		Code:     r.Code,
+		Message:  r.Message,
+		Span:     TokenSpan(r.Token),
+		Help:     r.Help,
+		Trace:    r.Trace,
+	}
 }
+
+// Limit names an execution budget of the interpreter.
+type Limit string
+
+const (
+	LimitTime        Limit = "time"        // The timeout or the deadline of the context passed
+	LimitSteps       Limit = "steps"       // Too many statements and expressions were executed
+	LimitAllocations Limit = "allocations" // Too many instances, lists, maps and strings were created
+	LimitCanceled    Limit = "canceled"    // The context was canceled
+)
+
+// LimitError reports that a program exceeded an execution budget, or that its execution was canceled.
+// Unlike a RuntimeError, it cannot be caught by Lox code.
+type LimitError struct {
+	Token   token.Token // The position of the statement being executed
+	Limit   Limit
+	Message string
+	Trace   []Frame // The call stack when the limit was exceeded, outermost call first
+	Cause   error   // The error of the context, for time limits and cancellation
+}
+
+func (e LimitError) Error() string {
+	return fmt.Sprintf("RUNTIME ERROR[%s] [%s:%d:%d] %s\n", CodeLimitExceeded, e.Token.File, e.Token.Line, e.Token.Column, e.Message)
+}
+
+// Unwrap returns the error of the context, so that errors.Is(err, context.DeadlineExceeded) works.
+func (e LimitError) Unwrap() error {
+	return e.Cause
+}
+
+// Diagnostic describes the error, pointing at the statement being executed when the limit was exceeded.
+func (e LimitError) Diagnostic() Diagnostic {
+	return Diagnostic{
+		Severity: SeverityError,
+		Phase:    PhaseRuntime,
+		Code:     CodeLimitExceeded,
+		Message:  e.Message,
+		Span:     TokenSpan(e.Token),
+		Notes:    []string{"exceeded limit: " + string(e.Limit)},
+		Trace:    e.Trace,
+	}
+}
+
+// InternalError reports a bug of the interpreter, such as a Go panic, at the position being executed
+type InternalError struct {
+	Token   token.Token
+	Message string
+}
+
+func (e InternalError) Error() string {
+	return fmt.Sprintf("INTERNAL ERROR[%s] [%s:%d:%d] %s\n", CodeInternalError, e.Token.File, e.Token.Line, e.Token.Column, e.Message)
+}
+
+// Diagnostic describes the error, pointing at the statement being executed when it occurred.
+func (e InternalError) Diagnostic() Diagnostic {
+	return Diagnostic{
+		Severity: SeverityError,
+		Phase:    PhaseRuntime,
+		Code:     CodeInternalError,
+		Message:  "Internal error: " + e.Message,
+		Span:     TokenSpan(e.Token),
+		Notes:    []string{"this is a bug in golox or in a Go function called by it, please report it together with the code that caused it"},
+	}
+}
+
+// Frame is an entry of the call stack: a call of the function named Function
+// made at the position of the Call token.
+type Frame struct {
+	Function string
+	Call     token.Token
+}
+
+// SourceLine returns the text of a line of a source file, if it is known.
+type SourceLine func(file string, line int) (string, bool)
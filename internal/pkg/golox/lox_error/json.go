@@ -0,0 +1,72 @@
+package lox_error
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// jsonPosition is a line and column in the JSON form of a diagnostic.
+type jsonPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// jsonNote is a related location, such as a label or a frame of the call stack.
+type jsonNote struct {
+	Message string       `json:"message"`
+	File    string       `json:"file"`
+	Start   jsonPosition `json:"start"`
+	End     jsonPosition `json:"end"`
+}
+
+// jsonDiagnostic is the machine-readable form of a Diagnostic.
+type jsonDiagnostic struct {
+	Severity string       `json:"severity"`
+	Phase    Phase        `json:"phase"`
+	Code     Code         `json:"code,omitempty"`
+	Message  string       `json:"message"`
+	File     string       `json:"file"`
+	Start    jsonPosition `json:"start"`
+	End      jsonPosition `json:"end"`
+	Notes    []string     `json:"notes"`
+	Help     string       `json:"help,omitempty"`
+	Related  []jsonNote   `json:"related,omitempty"` // Labels, then the call stack from the innermost call
+}
+
+func newJSONNote(message string, span Span) jsonNote {
+	return jsonNote{
+		Message: message,
+		File:    span.File,
+		Start:   jsonPosition{Line: span.StartLine, Column: span.StartColumn},
+		End:     jsonPosition{Line: span.EndLine, Column: span.EndColumn},
+	}
+}
+
+// MarshalJSON encodes the diagnostic as a flat JSON object for editors and CI tools.
+func (d Diagnostic) MarshalJSON() ([]byte, error) {
+	notes := d.Notes
+	if notes == nil {
+		notes = []string{}
+	}
+
+	object := jsonDiagnostic{
+		Severity: strings.ToLower(d.Severity.String()),
+		Phase:    d.Phase,
+		Code:     d.Code,
+		Message:  d.Message,
+		File:     d.Span.File,
+		Start:    jsonPosition{Line: d.Span.StartLine, Column: d.Span.StartColumn},
+		End:      jsonPosition{Line: d.Span.EndLine, Column: d.Span.EndColumn},
+		Notes:    notes,
+		Help:     d.Help,
+	}
+	for _, label := range d.Labels {
+		object.Related = append(object.Related, newJSONNote(label.Message, label.Span))
+	}
+	for i := len(d.Trace) - 1; i >= 0; i-- {
+		frame := d.Trace[i]
+		object.Related = append(object.Related, newJSONNote("in call to '"+frame.Function+"'", TokenSpan(frame.Call)))
+	}
+
+	return json.Marshal(object)
+}
@@ -0,0 +1,83 @@
+package lox_error
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/token"
+)
+
+func TestDiagnosticJSON(t *testing.T) {
+	call := token.NewToken(token.RIGHT_PAREN, ")", nil, "main.lox", 9, 8)
+	tests := []struct {
+		name string
+		err  Diagnosable
+		want string
+	}{
+		{
+			name: "scanner error",
+			err:  ScannerError{File: "main.lox", Line: 2, Column: 5, Code: CodeUnexpectedCharacter, Message: "Unexpected character '@'."},
+			want: `{"severity":"error","phase":"scanner","code":"E0001","message":"Unexpected character '@'.","file":"main.lox",` +
+				`"start":{"line":2,"column":5},"end":{"line":2,"column":5},"notes":[]}`,
+		},
+		{
+			name: "parser error at the end of the file",
+			err:  ParserError{Token: token.NewToken(token.EOF, "", nil, "main.lox", 3, 10), Code: CodeExpectedToken, Message: "Expect ';' after value."},
+			want: `{"severity":"error","phase":"parser","code":"E0201","message":"Expect ';' after value.","file":"main.lox",` +
+				`"start":{"line":3,"column":11},"end":{"line":3,"column":11},"notes":[]}`,
+		},
+		{
+			name: "runtime error with help and trace",
+			err: RuntimeError{
+				Token:   token.NewToken(token.IDENTIFIER, "count", nil, "lib.lox", 4, 12),
+				Code:    CodeUndefinedVariable,
+				Message: "Undefined variable 'count'.",
+				Help:    "did you mean 'counter'?",
+				Trace: []Frame{
+					{Function: "main", Call: call},
+					{Function: "helper", Call: token.NewToken(token.RIGHT_PAREN, ")", nil, "lib.lox", 20, 10)},
+				},
+			},
+			want: `{"severity":"error","phase":"runtime","code":"E0101","message":"Undefined variable 'count'.","file":"lib.lox",` +
+				`"start":{"line":4,"column":8},"end":{"line":4,"column":12},"notes":[],"help":"did you mean 'counter'?",` +
+				`"related":[{"message":"in call to 'helper'","file":"lib.lox","start":{"line":20,"column":10},"end":{"line":20,"column":10}},` +
+				`{"message":"in call to 'main'","file":"main.lox","start":{"line":9,"column":8},"end":{"line":9,"column":8}}]}`,
+		},
+		{
+			name: "limit error",
+			err:  LimitError{Token: token.NewToken(token.IDENTIFIER, "while", nil, "main.lox", 1, 5), Limit: LimitSteps, Message: "Execution limit exceeded: more than 10 steps."},
+			want: `{"severity":"error","phase":"runtime","code":"E0410","message":"Execution limit exceeded: more than 10 steps.","file":"main.lox",` +
+				`"start":{"line":1,"column":1},"end":{"line":1,"column":5},"notes":["exceeded limit: steps"]}`,
+		},
+		{
+			name: "warning with a label",
+			err: Warning{
+				Token:   token.NewToken(token.IDENTIFIER, "x", nil, "main.lox", 5, 9),
+				Code:    CodeShadowedVariable,
+				Message: "'x' shadows a variable of an enclosing scope.",
+				Labels:  []Label{{Span: TokenSpan(token.NewToken(token.IDENTIFIER, "x", nil, "main.lox", 2, 5)), Message: "shadowed declaration"}},
+			},
+			want: `{"severity":"warning","phase":"resolver","code":"W0004","message":"'x' shadows a variable of an enclosing scope.","file":"main.lox",` +
+				`"start":{"line":5,"column":9},"end":{"line":5,"column":9},"notes":[],"help":"add the comment '// lox:ignore shadow' to suppress this warning",` +
+				`"related":[{"message":"shadowed declaration","file":"main.lox","start":{"line":2,"column":5},"end":{"line":2,"column":5}}]}`,
+		},
+		{
+			name: "multi-line token",
+			err:  RuntimeError{Token: token.NewToken(token.STRING, "\"a\nbc\"", nil, "main.lox", 7, 3), Code: CodeInvalidOperand, Message: "Operand must be a number."},
+			want: `{"severity":"error","phase":"runtime","code":"E0401","message":"Operand must be a number.","file":"main.lox",` +
+				`"start":{"line":6,"column":1},"end":{"line":7,"column":3},"notes":[]}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := json.Marshal(test.err.Diagnostic())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != test.want {
+				t.Errorf("got\n%s\nwant\n%s", data, test.want)
+			}
+		})
+	}
+}
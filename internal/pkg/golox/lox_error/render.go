@@ -0,0 +1,205 @@
+package lox_error
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ANSI escape sequences used by the Renderer when colors are enabled.
+const (
+	ansiReset  = "\033[0m"
+	ansiBold   = "\033[1m"
+	ansiRed    = "\033[1;31m"
+	ansiYellow = "\033[1;33m"
+	ansiBlue   = "\033[1;34m"
+	ansiCyan   = "\033[1;36m"
+)
+
+// Renderer formats diagnostics for humans: a header with the position and the
+// message, the source lines with the offending code underlined, and the notes.
+//
+//	PARSER ERROR[E0201] [examples/04-syntax-err.lox:3:12] Expect ';' after variable declaration.
+//	  |
+//	3 |     var xy == x * y;
+//	  |            ^^
+type Renderer struct {
+	Source SourceLine // Looks up the source lines; the snippet is left out when it returns false
+	Color  bool       // Use ANSI colors, e.g. when writing to a terminal
+}
+
+// marker is an underlined span on a single source line.
+type marker struct {
+	start, end int // Columns, inclusive
+	primary    bool
+	message    string
+}
+
+// Render returns the text of the diagnostic, ending with an empty line.
+func (r Renderer) Render(d Diagnostic) string {
+	var builder strings.Builder
+	builder.WriteString(r.traceback(d))
+
+	severityColor := ansiRed
+	if d.Severity == SeverityWarning {
+		severityColor = ansiYellow
+	}
+	header := fmt.Sprintf("%s %s", strings.ToUpper(string(d.Phase)), d.Severity)
+	if d.Code != "" {
+		header += "[" + string(d.Code) + "]"
+	}
+	fmt.Fprintf(&builder, "%s [%s:%d:%d] %s\n",
+		r.paint(severityColor, header), d.Span.File, d.Span.StartLine, d.Span.StartColumn, r.paint(ansiBold, d.Message))
+
+	// Collect the markers of every line of the snippet.
+	markers := map[int][]marker{}
+	markers[d.Span.StartLine] = append(markers[d.Span.StartLine], spanMarker(d.Span, true, ""))
+	for _, label := range d.Labels {
+		if label.Span.File != d.Span.File {
+			continue
+		}
+		line := label.Span.StartLine
+		markers[line] = append(markers[line], spanMarker(label.Span, false, label.Message))
+	}
+
+	lines := make([]int, 0, len(markers))
+	for line := range markers {
+		if _, ok := r.sourceLine(d.Span.File, line); ok {
+			lines = append(lines, line)
+		}
+	}
+	sort.Ints(lines)
+
+	gutter := 0
+	if len(lines) > 0 {
+		gutter = len(strconv.Itoa(lines[len(lines)-1]))
+	}
+	padding := strings.Repeat(" ", gutter)
+
+	if len(lines) > 0 {
+		fmt.Fprintf(&builder, "%s %s\n", padding, r.paint(ansiBlue, "|"))
+	}
+	for i, line := range lines {
+		if i > 0 && line > lines[i-1]+1 {
+			fmt.Fprintf(&builder, "%s\n", r.paint(ansiBlue, "..."))
+		}
+		text, _ := r.sourceLine(d.Span.File, line)
+		fmt.Fprintf(&builder, "%s %s\n", r.paint(ansiBlue, fmt.Sprintf("%*d |", gutter, line)), text)
+		for _, m := range markers[line] {
+			fmt.Fprintf(&builder, "%s %s %s\n", padding, r.paint(ansiBlue, "|"), r.underline(text, m, severityColor))
+		}
+	}
+
+	for _, note := range d.Notes {
+		fmt.Fprintf(&builder, "%s %s %s\n", padding, r.paint(ansiBlue, "="), r.paint(ansiBold, "note:")+" "+note)
+	}
+	if d.Help != "" {
+		fmt.Fprintf(&builder, "%s %s %s\n", padding, r.paint(ansiBlue, "="), r.paint(ansiCyan, "help:")+" "+d.Help)
+	}
+
+	return builder.String()
+}
+
+// traceback formats the call stack of the diagnostic, most recent call last,
+// in the style of Python. Consecutive repetitions of the same frame, as in
+// deep recursion, are collapsed into one line. It returns an empty string if
+// the error did not occur inside a function.
+func (r Renderer) traceback(d Diagnostic) string {
+	if len(d.Trace) == 0 {
+		return ""
+	}
+
+	var builder strings.Builder
+	builder.WriteString("Traceback (most recent call last):\n")
+
+	previous := ""
+	repeated := 0
+	writeEntry := func(span Span, function string) {
+		var entry strings.Builder
+		fmt.Fprintf(&entry, "  File \"%s\", line %d, column %d, in %s\n", span.File, span.StartLine, span.StartColumn, function)
+		if line, ok := r.sourceLine(span.File, span.StartLine); ok {
+			fmt.Fprintf(&entry, "    %s\n", strings.TrimSpace(line))
+		}
+
+		if entry.String() == previous {
+			repeated++
+			return
+		}
+		writeRepeated(&builder, repeated)
+		repeated = 0
+		previous = entry.String()
+		builder.WriteString(previous)
+	}
+
+	function := "<module>"
+	for _, frame := range d.Trace {
+		writeEntry(TokenSpan(frame.Call), function)
+		function = frame.Function
+	}
+	writeEntry(d.Span, function)
+	writeRepeated(&builder, repeated)
+
+	return builder.String()
+}
+
+func writeRepeated(builder *strings.Builder, repeated int) {
+	if repeated > 0 {
+		fmt.Fprintf(builder, "  [Previous line repeated %d more times]\n", repeated)
+	}
+}
+
+// spanMarker returns the marker of the first line of a span. Spans over
+// several lines are underlined up to the end of their first line.
+func spanMarker(span Span, primary bool, message string) marker {
+	end := span.EndColumn
+	if span.EndLine != span.StartLine {
+		end = -1
+	}
+	return marker{start: span.StartColumn, end: end, primary: primary, message: message}
+}
+
+// underline draws the marker below the text of its line, '^' for the
+// primary span and '-' for labels, followed by the message of the label.
+func (r Renderer) underline(text string, m marker, severityColor string) string {
+	end := m.end
+	if end < 0 || end > len(text) {
+		end = max(len(text), m.start)
+	}
+
+	// Keep tabs so that the marker lines up with the source line.
+	var indent strings.Builder
+	for i := 0; i < m.start-1; i++ {
+		if i < len(text) && text[i] == '\t' {
+			indent.WriteByte('\t')
+		} else {
+			indent.WriteByte(' ')
+		}
+	}
+
+	char, color := "^", severityColor
+	if !m.primary {
+		char, color = "-", ansiBlue
+	}
+	underline := strings.Repeat(char, max(end-m.start+1, 1))
+	if m.message != "" {
+		underline += " " + m.message
+	}
+	return indent.String() + r.paint(color, underline)
+}
+
+func (r Renderer) sourceLine(file string, line int) (string, bool) {
+	if r.Source == nil {
+		return "", false
+	}
+	text, ok := r.Source(file, line)
+	return strings.TrimRight(text, "\r"), ok
+}
+
+// paint wraps text in an ANSI color if colors are enabled.
+func (r Renderer) paint(color string, text string) string {
+	if !r.Color {
+		return text
+	}
+	return color + text + ansiReset
+}
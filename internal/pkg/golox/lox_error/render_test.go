@@ -0,0 +1,147 @@
+package lox_error
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/token"
+)
+
+// testSource holds the lines of the file "main.lox" for the Renderer.
+var testSource = []string{
+	"var x = 1;",
+	"{",
+	"    var xy == x * y;",
+	"\tprint x;",
+	"}",
+	"fun f() { f(); }",
+	"f();",
+}
+
+func testSourceLine(file string, line int) (string, bool) {
+	if file != "main.lox" || line < 1 || line > len(testSource) {
+		return "", false
+	}
+	return testSource[line-1], true
+}
+
+func TestRender(t *testing.T) {
+	recursion := []Frame{{Function: "f", Call: token.NewToken(token.RIGHT_PAREN, ")", nil, "main.lox", 7, 3)}}
+	for i := 0; i < 3; i++ {
+		recursion = append(recursion, Frame{Function: "f", Call: token.NewToken(token.RIGHT_PAREN, ")", nil, "main.lox", 6, 13)})
+	}
+
+	tests := []struct {
+		name string
+		err  Diagnosable
+		want []string
+	}{
+		{
+			name: "parser error",
+			err:  ParserError{Token: token.NewToken(token.EQUAL_EQUAL, "==", nil, "main.lox", 3, 13), Code: CodeExpectedToken, Message: "Expect ';' after variable declaration."},
+			want: []string{
+				"PARSER ERROR[E0201] [main.lox:3:12] Expect ';' after variable declaration.",
+				"  |",
+				"3 |     var xy == x * y;",
+				"  |            ^^",
+			},
+		},
+		{
+			name: "unknown source",
+			err:  ScannerError{File: "other.lox", Line: 1, Column: 2, Code: CodeUnexpectedCharacter, Message: "Unexpected character '@'."},
+			want: []string{
+				"SCANNER ERROR[E0001] [other.lox:1:2] Unexpected character '@'.",
+			},
+		},
+		{
+			name: "tab before the span",
+			err:  RuntimeError{Token: token.NewToken(token.IDENTIFIER, "x", nil, "main.lox", 4, 8), Code: CodeUndefinedVariable, Message: "Undefined variable 'x'.", Help: "did you mean 'xs'?"},
+			want: []string{
+				"RUNTIME ERROR[E0101] [main.lox:4:8] Undefined variable 'x'.",
+				"  |",
+				"4 | \tprint x;",
+				"  | \t      ^",
+				"  = help: did you mean 'xs'?",
+			},
+		},
+		{
+			name: "warning with a label",
+			err: Warning{
+				Token:   token.NewToken(token.IDENTIFIER, "x", nil, "main.lox", 4, 8),
+				Code:    CodeShadowedVariable,
+				Message: "'x' shadows a global variable.",
+				Labels:  []Label{{Span: TokenSpan(token.NewToken(token.IDENTIFIER, "x", nil, "main.lox", 1, 5)), Message: "shadowed declaration"}},
+			},
+			want: []string{
+				"RESOLVER WARNING[W0004] [main.lox:4:8] 'x' shadows a global variable.",
+				"  |",
+				"1 | var x = 1;",
+				"  |     - shadowed declaration",
+				"...",
+				"4 | \tprint x;",
+				"  | \t      ^",
+				"  = help: add the comment '// lox:ignore shadow' to suppress this warning",
+			},
+		},
+		{
+			name: "span over several lines",
+			err:  RuntimeError{Token: token.NewToken(token.LEFT_BRACE, "{\n    var", nil, "main.lox", 3, 7), Code: CodeInvalidOperand, Message: "Invalid."},
+			want: []string{
+				"RUNTIME ERROR[E0401] [main.lox:2:1] Invalid.",
+				"  |",
+				"2 | {",
+				"  | ^",
+			},
+		},
+		{
+			name: "notes and repeated frames",
+			err:  LimitError{Token: token.NewToken(token.IDENTIFIER, "f", nil, "main.lox", 6, 11), Limit: LimitSteps, Message: "Execution limit exceeded: more than 10 steps.", Trace: recursion},
+			want: []string{
+				"Traceback (most recent call last):",
+				`  File "main.lox", line 7, column 3, in <module>`,
+				"    f();",
+				`  File "main.lox", line 6, column 13, in f`,
+				"    fun f() { f(); }",
+				"  [Previous line repeated 2 more times]",
+				`  File "main.lox", line 6, column 11, in f`,
+				"    fun f() { f(); }",
+				"RUNTIME ERROR[E0410] [main.lox:6:11] Execution limit exceeded: more than 10 steps.",
+				"  |",
+				"6 | fun f() { f(); }",
+				"  |           ^",
+				"  = note: exceeded limit: steps",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			renderer := Renderer{Source: testSourceLine}
+			got := renderer.Render(test.err.Diagnostic())
+			want := strings.Join(test.want, "\n") + "\n"
+			if got != want {
+				t.Errorf("got\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestRenderColor(t *testing.T) {
+	err := Warning{Token: token.NewToken(token.IDENTIFIER, "x", nil, "main.lox", 1, 5), Code: CodeUnusedVariable, Message: "Unused."}
+	plain := Renderer{Source: testSourceLine}.Render(err.Diagnostic())
+	colored := Renderer{Source: testSourceLine, Color: true}.Render(err.Diagnostic())
+
+	if strings.Contains(plain, "\033[") {
+		t.Errorf("plain output contains ANSI escapes:\n%q", plain)
+	}
+	if !strings.HasPrefix(colored, ansiYellow+"RESOLVER WARNING[W0001]"+ansiReset) {
+		t.Errorf("colored output does not start with a yellow header:\n%q", colored)
+	}
+	stripped := colored
+	for _, sequence := range []string{ansiReset, ansiBold, ansiRed, ansiYellow, ansiBlue, ansiCyan} {
+		stripped = strings.ReplaceAll(stripped, sequence, "")
+	}
+	if stripped != plain {
+		t.Errorf("colored output without escapes\n%s\ndiffers from\n%s", stripped, plain)
+	}
+}
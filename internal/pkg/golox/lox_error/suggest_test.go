@@ -0,0 +1,48 @@
+package lox_error
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []string
+		want       []string
+	}{
+		{name: "countr", candidates: []string{"counter", "count", "other"}, want: []string{"count", "counter"}},
+		{name: "lenght", candidates: []string{"length", "len"}, want: []string{"length"}},
+		{name: "x", candidates: []string{"y", "xs", "abc"}, want: []string{"xs", "y"}},
+		{name: "value", candidates: []string{"value", "valeu"}, want: []string{"valeu"}},
+		{name: "zzzz", candidates: []string{"print", "clock"}, want: []string{}},
+		{name: "ab", candidates: []string{"a", "b", "ac", "abc", "ba"}, want: []string{"a", "abc", "ac"}},
+		{name: "name", candidates: []string{"nam", "nam", "nmae", "nom"}, want: []string{"nam", "nmae"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Suggest(test.name, test.candidates); !slices.Equal(got, test.want) {
+				t.Errorf("Suggest(%q, %q) = %q, want %q", test.name, test.candidates, got, test.want)
+			}
+		})
+	}
+}
+
+func TestDidYouMean(t *testing.T) {
+	tests := []struct {
+		suggestions []string
+		want        string
+	}{
+		{nil, ""},
+		{[]string{"count"}, "did you mean 'count'?"},
+		{[]string{"count", "amount"}, "did you mean 'count' or 'amount'?"},
+		{[]string{"a", "b", "c"}, "did you mean 'a', 'b' or 'c'?"},
+	}
+
+	for _, test := range tests {
+		if got := DidYouMean(test.suggestions); got != test.want {
+			t.Errorf("DidYouMean(%q) = %q, want %q", test.suggestions, got, test.want)
+		}
+	}
+}
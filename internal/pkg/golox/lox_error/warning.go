@@ -0,0 +1,69 @@
+package lox_error
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/token"
+)
+
+// Warning reports code that is valid but probably a mistake, such as an unused variable.
+type Warning struct {
+	Token   token.Token
+	Code    Code
+	Message string
+	Labels  []Label // Related locations, e.g. the shadowed declaration
+}
+
+func (w Warning) Error() string {
+	return fmt.Sprintf("RESOLVER WARNING[%s] [%s:%d:%d] %s\n", w.Code, w.Token.File, w.Token.Line, w.Token.Column, w.Message)
+}
+
+// Diagnostic describes the warning, with a hint how to suppress it.
+func (w Warning) Diagnostic() Diagnostic {
+	return Diagnostic{
+		Severity: SeverityWarning,
+		Phase:    PhaseResolver,
+		Code:     w.Code,
+		Message:  w.Message,
+		Span:     TokenSpan(w.Token),
+		Labels:   w.Labels,
+		Help:     fmt.Sprintf("add the comment '// lox:ignore %s' to suppress this warning", WarningNames[w.Code]),
+	}
+}
+
+// WarningNames are the names of the warnings used in "lox:ignore" comments.
+var WarningNames = map[Code]string{
+	CodeUnusedVariable:         "unused",
+	CodeUnreadAssignment:       "unread",
+	CodeUnreachableCode:        "unreachable",
+	CodeShadowedVariable:       "shadow",
+	CodeSuperWithoutSuperclass: "super",
+}
+
+// Suppressions holds the warnings disabled by "// lox:ignore" comments, by line.
+//
+// A comment on a line of its own applies to the next line, a comment after
+// code applies to its own line. It lists the names or codes of the suppressed
+// warnings, or suppresses all of them if it lists none:
+//
+//	// lox:ignore unused, shadow
+//	var x = 1; // lox:ignore W0001
+type Suppressions map[int][]string
+
+// Suppresses reports whether the warning with the given code is disabled on the line.
+func (s Suppressions) Suppresses(line int, code Code) bool {
+	names, ok := s[line]
+	if !ok {
+		return false
+	}
+	if len(names) == 0 {
+		return true
+	}
+	for _, name := range names {
+		if name == WarningNames[code] || Code(strings.ToUpper(name)) == code {
+			return true
+		}
+	}
+	return false
+}
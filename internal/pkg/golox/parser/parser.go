@@ -1,8 +1,6 @@
 package parser
 
 import (
-	"fmt"
-
 	"github.com/mejroslav/golox/internal/pkg/golox/ast"
 	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
 	"github.com/mejroslav/golox/internal/pkg/golox/token"
@@ -13,6 +11,7 @@ type Parser struct {
 	tokens     []token.Token
 	statements []ast.Stmt
 	current    int
+	errors     []error // Errors found so far, reported by the caller
 }
 
 func NewParser(tokens []token.Token) *Parser {
@@ -25,7 +24,7 @@ func (p *Parser) Parse() ([]ast.Stmt, bool) {
 	for !p.isAtEnd() {
 		statement, err := p.declaration()
 		if err != nil {
-			fmt.Println(err.Error())
+			p.errors = append(p.errors, err)
 			p.synchronize()
 			hadError = true
 			continue
@@ -36,14 +35,20 @@ func (p *Parser) Parse() ([]ast.Stmt, bool) {
 	return p.statements, hadError
 }
 
+// Errors returns the errors found while parsing.
+func (p *Parser) Errors() []error {
+	return p.errors
+}
+
 // expression -> assignment ;
 func (p *Parser) expression() (ast.Expr, error) {
 	return p.assignment()
 }
 
-// assignment -> IDENTIFIER "=" assignment | logic_or ;
+// assignment -> target ( "=" | "+=" | "-=" | "*=" | "/=" | "%=" ) assignment | ternary ;
+// target     -> ( call "." )? IDENTIFIER | call "[" expression "]" ;
 func (p *Parser) assignment() (ast.Expr, error) {
-	expr, err := p.or()
+	expr, err := p.ternary()
 	if err != nil {
 		return nil, err
 	}
@@ -61,15 +66,89 @@ func (p *Parser) assignment() (ast.Expr, error) {
 			return &ast.Assign{Name: name, Value: value}, nil
 		} else if get, ok := expr.(*ast.Get); ok {
 			return &ast.Set{Object: get.Object, Name: get.Name, Value: value}, nil
+		} else if index, ok := expr.(*ast.Index); ok {
+			return &ast.SetIndex{Object: index.Object, Bracket: index.Bracket, Index: index.Index, Value: value}, nil
 		}
 
 		// TODO: We want to report the error, but continue parsing
 		return nil, lox_error.ParserError{
 			Token:   *equals,
+			Code:    lox_error.CodeInvalidAssignmentTarget,
 			Message: "Invalid assignment target.",
 		}
 	}
 
+	if p.match(token.PLUS_EQUAL, token.MINUS_EQUAL, token.STAR_EQUAL, token.SLASH_EQUAL, token.PERCENT_EQUAL) {
+		operator := p.previous()
+		value, err := p.assignment()
+		if err != nil {
+			return nil, err
+		}
+
+		if !isAssignmentTarget(expr) {
+			return nil, lox_error.ParserError{
+				Token:   *operator,
+				Code:    lox_error.CodeInvalidAssignmentTarget,
+				Message: "Invalid assignment target.",
+			}
+		}
+		return &ast.Compound{Target: expr, Operator: operator, Value: value}, nil
+	}
+
+	return expr, nil
+}
+
+// isAssignmentTarget checks if the expression is a variable, a field or a subscript.
+func isAssignmentTarget(expr ast.Expr) bool {
+	switch expr.(type) {
+	case *ast.Variable, *ast.Get, *ast.Index:
+		return true
+	}
+	return false
+}
+
+// ternary -> coalesce ( "?" expression ":" ternary )? ;
+func (p *Parser) ternary() (ast.Expr, error) {
+	expr, err := p.coalesce()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(token.QUESTION) {
+		thenBranch, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		_, err = p.consume(token.COLON, "Expect ':' after then branch of conditional expression.")
+		if err != nil {
+			return nil, err
+		}
+		elseBranch, err := p.ternary()
+		if err != nil {
+			return nil, err
+		}
+		expr = &ast.Conditional{Condition: expr, ThenBranch: thenBranch, ElseBranch: elseBranch}
+	}
+
+	return expr, nil
+}
+
+// coalesce -> or ( "??" or )* ;
+func (p *Parser) coalesce() (ast.Expr, error) {
+	expr, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(token.QUESTION_QUESTION) {
+		operator := p.previous()
+		right, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		expr = &ast.Logical{Left: expr, Operator: operator, Right: right}
+	}
+
 	return expr, nil
 }
 
@@ -111,15 +190,25 @@ func (p *Parser) and() (ast.Expr, error) {
 	return expr, nil
 }
 
-// declaration -> classDecl | varDecl | statement | function ;
+// declaration -> classDecl | varDecl | importDecl | statement | function ;
 func (p *Parser) declaration() (ast.Stmt, error) {
+	if p.match(token.IMPORT) {
+		return p.importDeclaration()
+	}
+	if p.checkWord("from") && p.checkNext(token.STRING) {
+		// 'from' is not reserved, but a name cannot be followed by a string.
+		p.advance()
+		return p.importDeclaration()
+	}
 	if p.match(token.CLASS) {
 		return p.classDeclaration()
 	}
 	if p.match(token.VAR) {
 		return p.varDeclaration()
 	}
-	if p.match(token.FUN) {
+	if p.check(token.FUN) && p.checkNext(token.IDENTIFIER) {
+		// Without a name, 'fun' starts an anonymous function expression.
+		p.advance()
 		return p.function("function")
 	}
 	return p.statement()
@@ -163,6 +252,51 @@ func (p *Parser) classDeclaration() (ast.Stmt, error) {
 	return &ast.Class{Name: &nameToken, Superclass: superclass, Methods: methods}, nil
 }
 
+// importDecl -> "import" STRING "as" IDENTIFIER ";" | "from" STRING "import" IDENTIFIER ( "," IDENTIFIER )* ";" ;
+func (p *Parser) importDeclaration() (ast.Stmt, error) {
+	keyword := p.previous()
+	path, err := p.consume(token.STRING, "Expect module path after '"+keyword.Lexeme+"'.")
+	if err != nil {
+		return nil, err
+	}
+
+	var alias *token.Token
+	names := []*token.Token{}
+	if keyword.Type == token.IMPORT {
+		if !p.checkWord("as") {
+			return nil, lox_error.ParserError{Token: *p.peek(), Code: lox_error.CodeExpectedToken, Message: "Expect 'as' after module path."}
+		}
+		p.advance()
+		aliasToken, err := p.consume(token.IDENTIFIER, "Expect module name after 'as'.")
+		if err != nil {
+			return nil, err
+		}
+		alias = &aliasToken
+	} else {
+		_, err = p.consume(token.IMPORT, "Expect 'import' after module path.")
+		if err != nil {
+			return nil, err
+		}
+		for {
+			nameToken, err := p.consume(token.IDENTIFIER, "Expect name to import.")
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, &nameToken)
+			if !p.match(token.COMMA) {
+				break
+			}
+		}
+	}
+
+	_, err = p.consume(token.SEMICOLON, "Expect ';' after import.")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.Import{Keyword: keyword, Path: &path, Alias: alias, Names: names}, nil
+}
+
 // varDecl -> "var" IDENTIFIER ( "=" expression )? ";" ;
 func (p *Parser) varDeclaration() (ast.Stmt, error) {
 	nameToken, err := p.consume(token.IDENTIFIER, "Expect variable name.")
@@ -186,7 +320,7 @@ func (p *Parser) varDeclaration() (ast.Stmt, error) {
 	return &ast.Var{Name: &nameToken, Initializer: initializer}, nil
 }
 
-// statement -> printStmt | forStmt | whileStmt | ifStmt | returnStmt | breakStmt | block | expressionStmt;
+// statement -> printStmt | forStmt | whileStmt | ifStmt | returnStmt | breakStmt | continueStmt | throwStmt | tryStmt | block | expressionStmt;
 func (p *Parser) statement() (ast.Stmt, error) {
 	if p.match(token.IF) {
 		return p.ifStatement()
@@ -206,6 +340,15 @@ func (p *Parser) statement() (ast.Stmt, error) {
 	if p.match(token.BREAK) {
 		return p.breakStatement()
 	}
+	if p.match(token.CONTINUE) {
+		return p.continueStatement()
+	}
+	if p.match(token.THROW) {
+		return p.throwStatement()
+	}
+	if p.match(token.TRY) {
+		return p.tryStatement()
+	}
 	if p.match(token.LEFT_BRACE) {
 		statements, err := p.block()
 		if err != nil {
@@ -231,6 +374,7 @@ func (p *Parser) printStatement() (ast.Stmt, error) {
 
 // whileStmt -> "while" "(" expression ")" statement ;
 func (p *Parser) whileStatement() (ast.Stmt, error) {
+	keyword := p.previous()
 	_, err := p.consume(token.LEFT_PAREN, "Expect '(' after 'while'.")
 	if err != nil {
 		return nil, err
@@ -251,11 +395,12 @@ func (p *Parser) whileStatement() (ast.Stmt, error) {
 		return nil, err
 	}
 
-	return &ast.While{Condition: condition, Body: body}, nil
+	return &ast.While{Keyword: keyword, Condition: condition, Body: body}, nil
 }
 
 // forStmt -> "for" "(" ( varDecl | expressionStmt | ";" ) expression? ";" expression? ")" statement ;
 func (p *Parser) forStatement() (ast.Stmt, error) {
+	keyword := p.previous()
 	_, err := p.consume(token.LEFT_PAREN, "Expect '(' after 'for'.")
 	if err != nil {
 		return nil, err
@@ -305,18 +450,12 @@ func (p *Parser) forStatement() (ast.Stmt, error) {
 		return nil, err
 	}
 
-	// Desugar for loop into while loop
-	if increment != nil {
-		body = &ast.Block{Statements: []ast.Stmt{
-			body,
-			&ast.Expression{Expression: increment},
-		}}
-	}
-
+	// Desugar for loop into while loop. The increment is kept separately
+	// on the while node, so that 'continue' does not skip it.
 	if condition == nil {
 		condition = &ast.Literal{Value: true}
 	}
-	body = &ast.While{Condition: condition, Body: body}
+	body = &ast.While{Keyword: keyword, Condition: condition, Body: body, Increment: increment}
 
 	if initializer != nil {
 		body = &ast.Block{Statements: []ast.Stmt{
@@ -338,6 +477,91 @@ func (p *Parser) breakStatement() (ast.Stmt, error) {
 	return &ast.Break{Keyword: keyword}, nil
 }
 
+// continueStmt -> "continue" ";" ;
+func (p *Parser) continueStatement() (ast.Stmt, error) {
+	keyword := p.previous()
+	_, err := p.consume(token.SEMICOLON, "Expect ';' after 'continue'.")
+	if err != nil {
+		return nil, err
+	}
+	return &ast.Continue{Keyword: keyword}, nil
+}
+
+// throwStmt -> "throw" expression ";" ;
+func (p *Parser) throwStatement() (ast.Stmt, error) {
+	keyword := p.previous()
+	value, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(token.SEMICOLON, "Expect ';' after thrown value.")
+	if err != nil {
+		return nil, err
+	}
+	return &ast.Throw{Keyword: keyword, Value: value}, nil
+}
+
+// tryStmt -> "try" block ( "catch" "(" IDENTIFIER ")" block )? ( "finally" block )? ;
+func (p *Parser) tryStatement() (ast.Stmt, error) {
+	keyword := p.previous()
+	_, err := p.consume(token.LEFT_BRACE, "Expect '{' after 'try'.")
+	if err != nil {
+		return nil, err
+	}
+	body, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+
+	var catchName *token.Token
+	var catchBody []ast.Stmt
+	if p.match(token.CATCH) {
+		_, err = p.consume(token.LEFT_PAREN, "Expect '(' after 'catch'.")
+		if err != nil {
+			return nil, err
+		}
+		name, err := p.consume(token.IDENTIFIER, "Expect exception variable name.")
+		if err != nil {
+			return nil, err
+		}
+		catchName = &name
+		_, err = p.consume(token.RIGHT_PAREN, "Expect ')' after exception variable name.")
+		if err != nil {
+			return nil, err
+		}
+		_, err = p.consume(token.LEFT_BRACE, "Expect '{' before catch body.")
+		if err != nil {
+			return nil, err
+		}
+		catchBody, err = p.block()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var finallyBody []ast.Stmt
+	if p.match(token.FINALLY) {
+		_, err = p.consume(token.LEFT_BRACE, "Expect '{' after 'finally'.")
+		if err != nil {
+			return nil, err
+		}
+		finallyBody, err = p.block()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if catchName == nil && finallyBody == nil {
+		return nil, lox_error.ParserError{
+			Token:   *keyword,
+			Code:    lox_error.CodeExpectedToken,
+			Message: "Expect 'catch' or 'finally' after try block.",
+		}
+	}
+
+	return &ast.Try{Body: body, CatchName: catchName, CatchBody: catchBody, FinallyBody: finallyBody}, nil
+}
+
 // ifStmt -> "if" "(" expression ")" statement ( "else" statement )? ;
 func (p *Parser) ifStatement() (ast.Stmt, error) {
 	_, err := p.consume(token.LEFT_PAREN, "Expect '(' after 'if'.")
@@ -417,12 +641,43 @@ func (p *Parser) function(kind string) (ast.Stmt, error) {
 		return nil, err
 	}
 
+	params, body, err := p.functionRest(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.Function{Name: &nameToken, Params: params, Body: body}, nil
+}
+
+// functionRest parses the parameters and the body of a function after the opening parenthesis.
+func (p *Parser) functionRest(kind string) ([]*token.Token, []ast.Stmt, error) {
+	params, err := p.parameters()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, err = p.consume(token.LEFT_BRACE, "Expect '{' before "+kind+" body.")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, err := p.block()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return params, body, nil
+}
+
+// parameters -> IDENTIFIER ( "," IDENTIFIER )* ")" ;
+func (p *Parser) parameters() ([]*token.Token, error) {
 	params := []*token.Token{}
 	if !p.check(token.RIGHT_PAREN) {
 		for {
 			if len(params) >= 255 {
 				return nil, lox_error.ParserError{
 					Token:   *p.peek(),
+					Code:    lox_error.CodeTooManyArguments,
 					Message: "Can't have more than 255 parameters.",
 				}
 			}
@@ -437,22 +692,70 @@ func (p *Parser) function(kind string) (ast.Stmt, error) {
 		}
 	}
 
-	_, err = p.consume(token.RIGHT_PAREN, "Expect ')' after parameters.")
+	_, err := p.consume(token.RIGHT_PAREN, "Expect ')' after parameters.")
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = p.consume(token.LEFT_BRACE, "Expect '{' before "+kind+" body.")
+	return params, nil
+}
+
+// lambda -> "fun" "(" parameters? ")" block ;
+func (p *Parser) lambda() (ast.Expr, error) {
+	keyword := p.previous()
+	_, err := p.consume(token.LEFT_PAREN, "Expect '(' after 'fun'.")
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := p.block()
+	params, body, err := p.functionRest("function")
 	if err != nil {
 		return nil, err
 	}
 
-	return &ast.Function{Name: &nameToken, Params: params, Body: body}, nil
+	return &ast.Lambda{Keyword: keyword, Function: &ast.Function{Params: params, Body: body}}, nil
+}
+
+// arrowFunction -> "(" parameters? ")" "=>" expression ;
+func (p *Parser) arrowFunction() (ast.Expr, error) {
+	params, err := p.parameters()
+	if err != nil {
+		return nil, err
+	}
+
+	arrow, err := p.consume(token.ARROW, "Expect '=>' after parameters.")
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	body := []ast.Stmt{&ast.Return{Keyword: &arrow, Value: value}}
+	return &ast.Lambda{Keyword: &arrow, Function: &ast.Function{Params: params, Body: body}}, nil
+}
+
+// isArrowFunction checks if the tokens after the current "(" form the parameter list of an arrow function.
+func (p *Parser) isArrowFunction() bool {
+	i := p.current
+	if p.tokens[i].Type != token.RIGHT_PAREN {
+		for {
+			if p.tokens[i].Type != token.IDENTIFIER {
+				return false
+			}
+			i++
+			if p.tokens[i].Type != token.COMMA {
+				break
+			}
+			i++
+		}
+		if p.tokens[i].Type != token.RIGHT_PAREN {
+			return false
+		}
+	}
+	return p.tokens[i+1].Type == token.ARROW
 }
 
 // block -> "{" declaration* "}" ;
@@ -494,16 +797,55 @@ func (p *Parser) equality() (ast.Expr, error) {
 	return expr, nil
 }
 
-// comparison -> term ( ( ">" | ">=" | "<" | "<=" ) term )* ;
+// comparison -> bitOr ( ( ">" | ">=" | "<" | "<=" ) bitOr )* ;
 func (p *Parser) comparison() (ast.Expr, error) {
-	expr, err := p.term()
+	expr, err := p.bitOr()
 	if err != nil {
 		return nil, err
 	}
 
 	for p.match(token.GREATER, token.GREATER_EQUAL, token.LESS, token.LESS_EQUAL) {
 		operator := p.previous()
-		right, err := p.term()
+		right, err := p.bitOr()
+		if err != nil {
+			return nil, err
+		}
+		expr = &ast.Binary{Left: expr, Operator: operator, Right: right}
+	}
+
+	return expr, nil
+}
+
+// bitOr -> bitXor ( "|" bitXor )* ;
+func (p *Parser) bitOr() (ast.Expr, error) {
+	return p.leftAssociative(p.bitXor, token.PIPE)
+}
+
+// bitXor -> bitAnd ( "^" bitAnd )* ;
+func (p *Parser) bitXor() (ast.Expr, error) {
+	return p.leftAssociative(p.bitAnd, token.CARET)
+}
+
+// bitAnd -> shift ( "&" shift )* ;
+func (p *Parser) bitAnd() (ast.Expr, error) {
+	return p.leftAssociative(p.shift, token.AMPERSAND)
+}
+
+// shift -> term ( ( "<<" | ">>" ) term )* ;
+func (p *Parser) shift() (ast.Expr, error) {
+	return p.leftAssociative(p.term, token.LESS_LESS, token.GREATER_GREATER)
+}
+
+// leftAssociative parses a sequence of operands separated by any of the given binary operators.
+func (p *Parser) leftAssociative(operand func() (ast.Expr, error), types ...token.TokenType) (ast.Expr, error) {
+	expr, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(types...) {
+		operator := p.previous()
+		right, err := operand()
 		if err != nil {
 			return nil, err
 		}
@@ -532,14 +874,14 @@ func (p *Parser) term() (ast.Expr, error) {
 	return expr, nil
 }
 
-// factor -> unary ( ( "/" | "*" ) unary )* ;
+// factor -> unary ( ( "/" | "*" | "%" | "~/" ) unary )* ;
 func (p *Parser) factor() (ast.Expr, error) {
 	expr, err := p.unary()
 	if err != nil {
 		return nil, err
 	}
 
-	for p.match(token.SLASH, token.STAR) {
+	for p.match(token.SLASH, token.STAR, token.PERCENT, token.TILDE_SLASH) {
 		operator := p.previous()
 		right, err := p.unary()
 		if err != nil {
@@ -551,9 +893,9 @@ func (p *Parser) factor() (ast.Expr, error) {
 	return expr, nil
 }
 
-// unary -> ( "!" | "-" ) unary | call ;
+// unary -> ( "!" | "-" | "~" ) unary | ( "++" | "--" ) unary | power ;
 func (p *Parser) unary() (ast.Expr, error) {
-	if p.match(token.BANG, token.MINUS) {
+	if p.match(token.BANG, token.MINUS, token.TILDE) {
 		operator := p.previous()
 		right, err := p.unary()
 		if err != nil {
@@ -562,16 +904,80 @@ func (p *Parser) unary() (ast.Expr, error) {
 		return &ast.Unary{Operator: operator, Right: right}, nil
 	}
 
-	return p.call()
+	if p.match(token.PLUS_PLUS, token.MINUS_MINUS) {
+		operator := p.previous()
+		target, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		if !isAssignmentTarget(target) {
+			return nil, lox_error.ParserError{
+				Token:   *operator,
+				Code:    lox_error.CodeInvalidAssignmentTarget,
+				Message: "Invalid increment or decrement target.",
+			}
+		}
+		return &ast.Update{Target: target, Operator: operator, Prefix: true}, nil
+	}
+
+	return p.power()
+}
+
+// power -> postfix ( "**" unary )? ;
+//
+// The right operand is parsed by unary(), which makes "**" right-associative
+// and lets "-2 ** 2" mean "-(2 ** 2)".
+func (p *Parser) power() (ast.Expr, error) {
+	expr, err := p.postfix()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(token.STAR_STAR) {
+		operator := p.previous()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		expr = &ast.Binary{Left: expr, Operator: operator, Right: right}
+	}
+
+	return expr, nil
 }
 
-// call -> primary ( "(" arguments? ")" | "." IDENTIFIER )* ;
+// postfix -> call ( "++" | "--" )? ;
+func (p *Parser) postfix() (ast.Expr, error) {
+	expr, err := p.call()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(token.PLUS_PLUS, token.MINUS_MINUS) {
+		operator := p.previous()
+		if !isAssignmentTarget(expr) {
+			return nil, lox_error.ParserError{
+				Token:   *operator,
+				Code:    lox_error.CodeInvalidAssignmentTarget,
+				Message: "Invalid increment or decrement target.",
+			}
+		}
+		return &ast.Update{Target: expr, Operator: operator, Prefix: false}, nil
+	}
+
+	return expr, nil
+}
+
+// call -> primary ( "(" arguments? ")" | ( "." | "?." ) IDENTIFIER | "[" expression "]" )* ;
+//
+// If the chain contains "?.", it is wrapped in a Chain expression, so that
+// a nil object skips the rest of the chain.
 func (p *Parser) call() (ast.Expr, error) {
 	expr, err := p.primary()
 	if err != nil {
 		return nil, err
 	}
 
+	optional := false
 	for {
 		if p.match(token.LEFT_PAREN) {
 			expr, err = p.finishCall(expr)
@@ -584,11 +990,33 @@ func (p *Parser) call() (ast.Expr, error) {
 				return nil, err
 			}
 			expr = &ast.Get{Object: expr, Name: &nameToken}
+		} else if p.match(token.QUESTION_DOT) {
+			nameToken, err := p.consume(token.IDENTIFIER, "Expect property name after '?.'.")
+			if err != nil {
+				return nil, err
+			}
+			expr = &ast.OptionalGet{Object: expr, Name: &nameToken}
+			optional = true
+		} else if p.match(token.LEFT_BRACKET) {
+			bracket := p.previous()
+			index, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			_, err = p.consume(token.RIGHT_BRACKET, "Expect ']' after index.")
+			if err != nil {
+				return nil, err
+			}
+			expr = &ast.Index{Object: expr, Bracket: bracket, Index: index}
 		} else {
 			break
 		}
 	}
 
+	if optional {
+		expr = &ast.Chain{Expression: expr}
+	}
+
 	return expr, nil
 }
 
@@ -600,6 +1028,7 @@ func (p *Parser) finishCall(callee ast.Expr) (ast.Expr, error) {
 			if len(arguments) >= 255 {
 				return nil, lox_error.ParserError{
 					Token:   *p.peek(),
+					Code:    lox_error.CodeTooManyArguments,
 					Message: "Can't have more than 255 arguments.",
 				}
 			}
@@ -622,7 +1051,7 @@ func (p *Parser) finishCall(callee ast.Expr) (ast.Expr, error) {
 	return &ast.Call{Callee: callee, Paren: &paren, Arguments: arguments}, nil
 }
 
-// primary -> "true" | "false" | "nil" | NUMBER | STRING | "(" expression ")" | this | IDENTIFIER | "super" "." IDENTIFIER ;
+// primary -> "true" | "false" | "nil" | NUMBER | STRING | interpolation | lambda | arrowFunction | "(" expression ")" | list | map | this | IDENTIFIER | "super" "." IDENTIFIER ;
 func (p *Parser) primary() (ast.Expr, error) {
 	if p.match(token.FALSE) {
 		return &ast.Literal{Value: false}, nil
@@ -636,7 +1065,16 @@ func (p *Parser) primary() (ast.Expr, error) {
 	if p.match(token.NUMBER, token.STRING) {
 		return &ast.Literal{Value: p.previous().Literal}, nil
 	}
+	if p.match(token.INTERPOLATION) {
+		return p.interpolation()
+	}
+	if p.match(token.FUN) {
+		return p.lambda()
+	}
 	if p.match(token.LEFT_PAREN) {
+		if p.isArrowFunction() {
+			return p.arrowFunction()
+		}
 		expr, err := p.expression()
 		if err != nil {
 			return nil, err
@@ -647,6 +1085,14 @@ func (p *Parser) primary() (ast.Expr, error) {
 		}
 		return &ast.Grouping{Expression: expr}, nil
 	}
+	if p.match(token.LEFT_BRACKET) {
+		return p.list()
+	}
+	if p.match(token.LEFT_BRACE) {
+		// In expression position a brace can only start a map literal,
+		// blocks are handled by statement().
+		return p.mapLiteral()
+	}
 	if p.match(token.IDENTIFIER) {
 		return &ast.Variable{Name: p.previous()}, nil
 	}
@@ -668,11 +1114,92 @@ func (p *Parser) primary() (ast.Expr, error) {
 
 	err := lox_error.ParserError{
 		Token:   *p.peek(),
+		Code:    lox_error.CodeExpectedExpression,
 		Message: "Expect expression.",
 	}
 	return nil, err
 }
 
+// interpolation -> ( INTERPOLATION expression )+ STRING ;
+func (p *Parser) interpolation() (ast.Expr, error) {
+	parts := []ast.Expr{}
+	for {
+		parts = append(parts, &ast.Literal{Value: p.previous().Literal})
+		expr, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, expr)
+		if !p.match(token.INTERPOLATION) {
+			break
+		}
+	}
+
+	end, err := p.consume(token.STRING, "Expect '}' after interpolated expression.")
+	if err != nil {
+		return nil, err
+	}
+	parts = append(parts, &ast.Literal{Value: end.Literal})
+
+	return &ast.Interpolation{Parts: parts}, nil
+}
+
+// list -> "[" ( expression ( "," expression )* ","? )? "]" ;
+func (p *Parser) list() (ast.Expr, error) {
+	bracket := p.previous()
+	elements := []ast.Expr{}
+	for !p.check(token.RIGHT_BRACKET) && !p.isAtEnd() {
+		element, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, element)
+		if !p.match(token.COMMA) {
+			break
+		}
+	}
+
+	_, err := p.consume(token.RIGHT_BRACKET, "Expect ']' after list elements.")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.List{Bracket: bracket, Elements: elements}, nil
+}
+
+// map -> "{" ( expression ":" expression ( "," expression ":" expression )* ","? )? "}" ;
+func (p *Parser) mapLiteral() (ast.Expr, error) {
+	brace := p.previous()
+	keys := []ast.Expr{}
+	values := []ast.Expr{}
+	for !p.check(token.RIGHT_BRACE) && !p.isAtEnd() {
+		key, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		_, err = p.consume(token.COLON, "Expect ':' after map key.")
+		if err != nil {
+			return nil, err
+		}
+		value, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+		values = append(values, value)
+		if !p.match(token.COMMA) {
+			break
+		}
+	}
+
+	_, err := p.consume(token.RIGHT_BRACE, "Expect '}' after map entries.")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.Map{Brace: brace, Keys: keys, Values: values}, nil
+}
+
 // Helper methods
 
 // match checks if the current token is of any given types
@@ -691,7 +1218,7 @@ func (p *Parser) consume(t token.TokenType, message string) (token.Token, error)
 	if p.check(t) {
 		return *p.advance(), nil
 	}
-	return token.Token{}, lox_error.ParserError{Token: *p.peek(), Message: message}
+	return token.Token{}, lox_error.ParserError{Token: *p.peek(), Code: lox_error.CodeExpectedToken, Message: message}
 }
 
 // check checks if the current token is of the given type
@@ -702,6 +1229,20 @@ func (p *Parser) check(t token.TokenType) bool {
 	return p.peek().Type == t
 }
 
+// checkWord checks if the current token is the identifier word, which has a
+// meaning only in some places, like 'as' in an import declaration
+func (p *Parser) checkWord(word string) bool {
+	return p.check(token.IDENTIFIER) && p.peek().Lexeme == word
+}
+
+// checkNext checks if the token after the current one is of the given type
+func (p *Parser) checkNext(t token.TokenType) bool {
+	if p.isAtEnd() || p.tokens[p.current+1].Type == token.EOF {
+		return false
+	}
+	return p.tokens[p.current+1].Type == t
+}
+
 // advance moves to the next token and returns the previous one
 func (p *Parser) advance() *token.Token {
 	if !p.isAtEnd() {
@@ -737,7 +1278,7 @@ func (p *Parser) synchronize() {
 		}
 
 		switch p.peek().Type {
-		case token.CLASS, token.FUN, token.VAR, token.FOR, token.IF, token.WHILE, token.PRINT, token.RETURN, token.BREAK:
+		case token.CLASS, token.FUN, token.VAR, token.IMPORT, token.FOR, token.IF, token.WHILE, token.PRINT, token.RETURN, token.BREAK, token.CONTINUE, token.THROW, token.TRY:
 			return
 		}
 
@@ -13,10 +13,14 @@ import (
 // It determines the scope depth of each variable and informs the interpreter.
 type Resolver struct {
 	interpreter      *interpreter.Interpreter // The interpreter to resolve variables for
-	scopeStack       *utils.Stack             // Stack of scopes. Each scope is a map of variable names to their defined status
+	scopeStack       *utils.Stack             // Stack of scopes. Each scope is a map of variable names to their state
 	currentFunction  types.FunctionType       // The type of the current function being resolved
 	currentClass     types.ClassType          // The type of the current class being resolved
 	currentLoopDepth int                      // The current depth of nested loops
+	globals          map[string]bool          // Names declared by the top-level statements being resolved
+	errors           []error                  // Errors found so far, reported by the caller
+	warnings         []error                  // Warnings found so far, see Warnings
+	suppressions     lox_error.Suppressions   // Warnings disabled by "lox:ignore" comments
 }
 
 func NewResolver(interpreter *interpreter.Interpreter) *Resolver {
@@ -26,24 +30,44 @@ func NewResolver(interpreter *interpreter.Interpreter) *Resolver {
 		currentFunction:  types.FT_NONE,
 		currentClass:     types.CT_NONE,
 		currentLoopDepth: 0,
+		globals:          make(map[string]bool),
 	}
 }
 
-func (r *Resolver) Resolve(statements []ast.Stmt) ([]ast.Stmt, error) {
+// Resolve resolves the variables of the statements and reports whether any errors were found.
+//
+// Resolution does not stop at the first error, so that all of them can be
+// reported at once; see Errors.
+func (r *Resolver) Resolve(statements []ast.Stmt) ([]ast.Stmt, bool) {
+	for _, name := range declaredNames(statements) {
+		r.globals[name] = true
+	}
+	r.checkReachable(statements)
 	for _, statement := range statements {
 		if err := r.resolveStmt(statement); err != nil {
-			return nil, err
+			r.errors = append(r.errors, err)
 		}
 	}
-	return statements, nil
+	return statements, len(r.errors) > 0
+}
+
+// Errors returns the errors found while resolving.
+func (r *Resolver) Errors() []error {
+	return r.errors
+}
+
+// error records an error and lets the resolution continue.
+func (r *Resolver) error(name token.Token, code lox_error.Code, message string) {
+	r.errors = append(r.errors, lox_error.NewResolverError(name, code, message))
 }
 
 func (r *Resolver) BeginScope() {
-	r.scopeStack.Push(make(map[string]bool))
+	r.scopeStack.Push(make(map[string]*variable))
 }
 
 func (r *Resolver) EndScope() {
-	r.scopeStack.Pop()
+	scope := r.scopeStack.Pop().(map[string]*variable)
+	r.checkUnused(scope)
 }
 
 func (r *Resolver) resolveStmt(statement ast.Stmt) error {
@@ -57,14 +81,20 @@ func (r *Resolver) resolveExpr(expression ast.Expr) error {
 }
 
 func (r *Resolver) VisitBlockStmt(s *ast.Block) (any, error) {
+	return nil, r.resolveBlock(s.Statements)
+}
+
+// resolveBlock resolves a list of statements in a new scope.
+func (r *Resolver) resolveBlock(statements []ast.Stmt) error {
 	r.BeginScope()
-	for _, stmt := range s.Statements {
+	r.checkReachable(statements)
+	for _, stmt := range statements {
 		if err := r.resolveStmt(stmt); err != nil {
-			return nil, err
+			return err
 		}
 	}
 	r.EndScope()
-	return nil, nil
+	return nil
 }
 
 func (r *Resolver) VisitClassStmt(stmt *ast.Class) (any, error) {
@@ -73,18 +103,11 @@ func (r *Resolver) VisitClassStmt(stmt *ast.Class) (any, error) {
 	lastLoopDepth := r.currentLoopDepth
 	r.currentLoopDepth = 0
 
-	err := r.declare(stmt.Name)
-	if err != nil {
-		return nil, err
-	}
-
-	err = r.define(stmt.Name)
-	if err != nil {
-		return nil, err
-	}
+	r.declare(stmt.Name, kindClass)
+	r.define(stmt.Name)
 
 	if stmt.Superclass != nil && stmt.Name.Lexeme == stmt.Superclass.Name.Lexeme {
-		return nil, lox_error.NewRuntimeError(*stmt.Superclass.Name, "A class cannot inherit from itself.")
+		r.error(*stmt.Superclass.Name, lox_error.CodeSelfInheritance, "A class cannot inherit from itself.")
 	} else if stmt.Superclass != nil {
 		r.currentClass = types.CT_SUBCLASS
 		r.resolveExpr(stmt.Superclass)
@@ -93,11 +116,11 @@ func (r *Resolver) VisitClassStmt(stmt *ast.Class) (any, error) {
 	if stmt.Superclass != nil {
 		// r.currentClass = CT_SUBCLASS
 		r.BeginScope() // Scope for "super"
-		r.scopeStack.Peek().(map[string]bool)["super"] = true
+		r.scopeStack.Peek().(map[string]*variable)["super"] = &variable{defined: true}
 	}
 
 	r.BeginScope() // Scope for "this"
-	r.scopeStack.Peek().(map[string]bool)["this"] = true
+	r.scopeStack.Peek().(map[string]*variable)["this"] = &variable{defined: true}
 
 	for _, method := range stmt.Methods {
 		functionType := types.FT_METHOD
@@ -105,8 +128,7 @@ func (r *Resolver) VisitClassStmt(stmt *ast.Class) (any, error) {
 			functionType = types.FT_INITIALIZER
 		}
 
-		err = r.resolveFunction(&method, functionType)
-		if err != nil {
+		if err := r.resolveFunction(&method, functionType); err != nil {
 			return nil, err
 		}
 	}
@@ -122,10 +144,7 @@ func (r *Resolver) VisitClassStmt(stmt *ast.Class) (any, error) {
 }
 
 func (r *Resolver) VisitVarStmt(stmt *ast.Var) (any, error) {
-	err := r.declare(stmt.Name)
-	if err != nil {
-		return nil, err
-	}
+	r.declare(stmt.Name, kindVariable)
 
 	if stmt.Initializer != nil {
 		if err := r.resolveExpr(stmt.Initializer); err != nil {
@@ -133,23 +152,33 @@ func (r *Resolver) VisitVarStmt(stmt *ast.Var) (any, error) {
 		}
 	}
 
-	err = r.define(stmt.Name)
-	if err != nil {
-		return nil, err
-	}
+	r.define(stmt.Name)
+	return nil, nil
+}
 
+func (r *Resolver) VisitImportStmt(stmt *ast.Import) (any, error) {
+	names := stmt.Names
+	if stmt.Alias != nil {
+		names = []*token.Token{stmt.Alias}
+	}
+	for _, name := range names {
+		r.declare(name, kindImport)
+		r.define(name)
+	}
 	return nil, nil
 }
 
 func (r *Resolver) VisitVariableExpr(expr *ast.Variable) (any, error) {
 	if !r.scopeStack.IsEmpty() {
-		scope := r.scopeStack.Peek().(map[string]bool)
-		if defined, ok := scope[expr.Name.Lexeme]; ok && !defined {
-			return nil, lox_error.NewRuntimeError(*expr.Name, "Cannot read local variable in its own initializer.")
+		scope := r.scopeStack.Peek().(map[string]*variable)
+		if v, ok := scope[expr.Name.Lexeme]; ok && !v.defined {
+			r.error(*expr.Name, lox_error.CodeOwnInitializer, "Cannot read local variable in its own initializer.")
 		}
 	}
 
-	r.resolveLocal(expr, expr.Name)
+	if v := r.resolveLocal(expr, expr.Name); v != nil {
+		v.read = true
+	}
 
 	return nil, nil
 }
@@ -159,7 +188,9 @@ func (r *Resolver) VisitAssignExpr(expr *ast.Assign) (any, error) {
 		return nil, err
 	}
 
-	r.resolveLocal(expr, expr.Name)
+	if v := r.resolveLocal(expr, expr.Name); v != nil {
+		v.assignments = append(v.assignments, expr.Name)
+	}
 
 	return nil, nil
 }
@@ -167,21 +198,41 @@ func (r *Resolver) VisitAssignExpr(expr *ast.Assign) (any, error) {
 func (r *Resolver) VisitFunctionStmt(stmt *ast.Function) (any, error) {
 	lastLoopDepth := r.currentLoopDepth
 	r.currentLoopDepth = 0
-	err := r.declare(stmt.Name)
-	if err != nil {
+	r.declare(stmt.Name, kindFunction)
+	r.define(stmt.Name)
+
+	if err := r.resolveFunction(stmt, types.FT_FUNCTION); err != nil {
 		return nil, err
 	}
 
-	err = r.define(stmt.Name)
-	if err != nil {
+	r.currentLoopDepth = lastLoopDepth
+	return nil, nil
+}
+
+func (r *Resolver) VisitCompoundExpr(expr *ast.Compound) (any, error) {
+	if err := r.resolveExpr(expr.Value); err != nil {
 		return nil, err
 	}
+	if err := r.resolveExpr(expr.Target); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
 
-	err = r.resolveFunction(stmt, types.FT_FUNCTION)
-	if err != nil {
+func (r *Resolver) VisitUpdateExpr(expr *ast.Update) (any, error) {
+	if err := r.resolveExpr(expr.Target); err != nil {
 		return nil, err
 	}
+	return nil, nil
+}
 
+func (r *Resolver) VisitLambdaExpr(expr *ast.Lambda) (any, error) {
+	lastLoopDepth := r.currentLoopDepth
+	r.currentLoopDepth = 0
+	err := r.resolveFunction(expr.Function, types.FT_FUNCTION)
+	if err != nil {
+		return nil, err
+	}
 	r.currentLoopDepth = lastLoopDepth
 	return nil, nil
 }
@@ -227,23 +278,69 @@ func (r *Resolver) VisitWhileStmt(stmt *ast.While) (any, error) {
 	if err := r.resolveStmt(stmt.Body); err != nil {
 		return nil, err
 	}
+	if stmt.Increment != nil {
+		if err := r.resolveExpr(stmt.Increment); err != nil {
+			return nil, err
+		}
+	}
 	return nil, nil
 }
 
 func (r *Resolver) VisitBreakStmt(stmt *ast.Break) (any, error) {
 	if r.currentLoopDepth == 0 {
-		return nil, lox_error.NewRuntimeError(*stmt.Keyword, "Cannot use 'break' outside of a loop.")
+		r.error(*stmt.Keyword, lox_error.CodeLoopControlOutsideLoop, "Cannot use 'break' outside of a loop.")
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitContinueStmt(stmt *ast.Continue) (any, error) {
+	if r.currentLoopDepth == 0 {
+		r.error(*stmt.Keyword, lox_error.CodeLoopControlOutsideLoop, "Cannot use 'continue' outside of a loop.")
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitThrowStmt(stmt *ast.Throw) (any, error) {
+	if err := r.resolveExpr(stmt.Value); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitTryStmt(stmt *ast.Try) (any, error) {
+	if err := r.resolveBlock(stmt.Body); err != nil {
+		return nil, err
+	}
+
+	if stmt.CatchName != nil {
+		// The exception variable lives in the same scope as the catch body.
+		r.BeginScope()
+		r.declare(stmt.CatchName, kindVariable)
+		r.define(stmt.CatchName)
+		r.checkReachable(stmt.CatchBody)
+		for _, catchStmt := range stmt.CatchBody {
+			if err := r.resolveStmt(catchStmt); err != nil {
+				return nil, err
+			}
+		}
+		r.EndScope()
+	}
+
+	if stmt.FinallyBody != nil {
+		if err := r.resolveBlock(stmt.FinallyBody); err != nil {
+			return nil, err
+		}
 	}
 	return nil, nil
 }
 
 func (r *Resolver) VisitReturnStmt(stmt *ast.Return) (any, error) {
 	if r.currentFunction == types.FT_NONE {
-		return nil, lox_error.NewRuntimeError(*stmt.Keyword, "Cannot return from top-level code.")
+		r.error(*stmt.Keyword, lox_error.CodeTopLevelReturn, "Cannot return from top-level code.")
 	}
 	if stmt.Value != nil {
 		if r.currentFunction == types.FT_INITIALIZER {
-			return nil, lox_error.NewRuntimeError(*stmt.Keyword, "Cannot return a value from an initializer.")
+			r.error(*stmt.Keyword, lox_error.CodeInitializerReturn, "Cannot return a value from an initializer.")
 		}
 		if err := r.resolveExpr(stmt.Value); err != nil {
 			return nil, err
@@ -280,6 +377,33 @@ func (r *Resolver) VisitGetExpr(expr *ast.Get) (any, error) {
 	return nil, nil
 }
 
+func (r *Resolver) VisitOptionalGetExpr(expr *ast.OptionalGet) (any, error) {
+	if err := r.resolveExpr(expr.Object); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitChainExpr(expr *ast.Chain) (any, error) {
+	if err := r.resolveExpr(expr.Expression); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitConditionalExpr(expr *ast.Conditional) (any, error) {
+	if err := r.resolveExpr(expr.Condition); err != nil {
+		return nil, err
+	}
+	if err := r.resolveExpr(expr.ThenBranch); err != nil {
+		return nil, err
+	}
+	if err := r.resolveExpr(expr.ElseBranch); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
 func (r *Resolver) VisitSetExpr(expr *ast.Set) (any, error) {
 	if err := r.resolveExpr(expr.Value); err != nil {
 		return nil, err
@@ -292,9 +416,12 @@ func (r *Resolver) VisitSetExpr(expr *ast.Set) (any, error) {
 
 func (r *Resolver) VisitSuperExpr(expr *ast.Super) (any, error) {
 	if r.currentClass == types.CT_NONE {
-		return nil, lox_error.NewRuntimeError(*expr.Keyword, "Cannot use 'super' outside of a class.")
+		r.error(*expr.Keyword, lox_error.CodeInvalidSuper, "Cannot use 'super' outside of a class.")
+		return nil, nil
 	} else if r.currentClass != types.CT_SUBCLASS {
-		return nil, lox_error.NewRuntimeError(*expr.Keyword, "Cannot use 'super' in a class with no superclass.")
+		r.error(*expr.Keyword, lox_error.CodeInvalidSuper, "Cannot use 'super' in a class with no superclass.")
+		r.warn(*expr.Keyword, lox_error.CodeSuperWithoutSuperclass, "'super' is used in a class with no superclass.")
+		return nil, nil
 	}
 	r.resolveLocal(expr, expr.Keyword)
 	return nil, nil
@@ -302,7 +429,8 @@ func (r *Resolver) VisitSuperExpr(expr *ast.Super) (any, error) {
 
 func (r *Resolver) VisitThisExpr(expr *ast.This) (any, error) {
 	if r.currentClass == types.CT_NONE {
-		return nil, lox_error.NewRuntimeError(*expr.Keyword, "Cannot use 'this' outside of a class.")
+		r.error(*expr.Keyword, lox_error.CodeThisOutsideClass, "Cannot use 'this' outside of a class.")
+		return nil, nil
 	}
 	r.resolveLocal(expr, expr.Keyword)
 	return nil, nil
@@ -319,6 +447,15 @@ func (r *Resolver) VisitLiteralExpr(expr *ast.Literal) (any, error) {
 	return nil, nil
 }
 
+func (r *Resolver) VisitInterpolationExpr(expr *ast.Interpolation) (any, error) {
+	for _, part := range expr.Parts {
+		if err := r.resolveExpr(part); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
 func (r *Resolver) VisitUnaryExpr(expr *ast.Unary) (any, error) {
 	if err := r.resolveExpr(expr.Right); err != nil {
 		return nil, err
@@ -336,58 +473,144 @@ func (r *Resolver) VisitLogicalExpr(expr *ast.Logical) (any, error) {
 	return nil, nil
 }
 
-func (r *Resolver) declare(name *token.Token) error {
+func (r *Resolver) VisitListExpr(expr *ast.List) (any, error) {
+	for _, element := range expr.Elements {
+		if err := r.resolveExpr(element); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitMapExpr(expr *ast.Map) (any, error) {
+	for i := range expr.Keys {
+		if err := r.resolveExpr(expr.Keys[i]); err != nil {
+			return nil, err
+		}
+		if err := r.resolveExpr(expr.Values[i]); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitIndexExpr(expr *ast.Index) (any, error) {
+	if err := r.resolveExpr(expr.Object); err != nil {
+		return nil, err
+	}
+	if err := r.resolveExpr(expr.Index); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitSetIndexExpr(expr *ast.SetIndex) (any, error) {
+	if err := r.resolveExpr(expr.Value); err != nil {
+		return nil, err
+	}
+	if err := r.resolveExpr(expr.Object); err != nil {
+		return nil, err
+	}
+	if err := r.resolveExpr(expr.Index); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (r *Resolver) declare(name *token.Token, kind variableKind) {
 	if r.scopeStack.IsEmpty() {
-		return nil
+		return
 	}
-	scope := r.scopeStack.Peek().(map[string]bool)
+	scope := r.scopeStack.Peek().(map[string]*variable)
 	if _, ok := scope[name.Lexeme]; ok {
-		return lox_error.NewRuntimeError(*name, "Variable with name '"+name.Lexeme+"' already declared in this scope.")
+		r.error(*name, lox_error.CodeAlreadyDeclared, "Variable with name '"+name.Lexeme+"' already declared in this scope.")
+	} else {
+		r.checkShadowing(name)
 	}
-	scope[name.Lexeme] = false
-	return nil
+	scope[name.Lexeme] = &variable{name: name, kind: kind}
 }
 
-func (r *Resolver) define(name *token.Token) error {
+func (r *Resolver) define(name *token.Token) {
 	if r.scopeStack.IsEmpty() {
-		return nil
+		return
 	}
-	scope := r.scopeStack.Peek().(map[string]bool)
-	scope[name.Lexeme] = true
-	return nil
+	scope := r.scopeStack.Peek().(map[string]*variable)
+	scope[name.Lexeme].defined = true
 }
 
 // resolveLocal resolves a local variable by determining its scope depth.
-func (r *Resolver) resolveLocal(expr ast.Expr, name *token.Token) error {
+// It returns the variable, or nil if the variable is global.
+func (r *Resolver) resolveLocal(expr ast.Expr, name *token.Token) *variable {
 	for i := r.scopeStack.Size() - 1; i >= 0; i-- {
 		scope, ok := r.scopeStack.Get(i)
 		if !ok {
 			continue
 		}
-		if _, ok := scope.(map[string]bool)[name.Lexeme]; ok {
+		if v, ok := scope.(map[string]*variable)[name.Lexeme]; ok {
 			r.interpreter.Resolve(expr, r.scopeStack.Size()-1-i)
-			return nil
+			return v
 		}
 	}
 
+	// The variable is global. If it is not declared at the top level either,
+	// it is probably misspelled, so look for similar names in scope now, as
+	// the runtime only sees the variables defined so far.
+	if !r.globals[name.Lexeme] {
+		if suggestions := lox_error.Suggest(name.Lexeme, r.visibleNames()); len(suggestions) > 0 {
+			r.interpreter.Suggest(expr, suggestions)
+		}
+	}
 	return nil
 }
 
+// visibleNames returns the names of the local variables in all enclosing scopes and of the top-level declarations.
+func (r *Resolver) visibleNames() []string {
+	names := []string{}
+	for i := 0; i < r.scopeStack.Size(); i++ {
+		scope, _ := r.scopeStack.Get(i)
+		for name := range scope.(map[string]*variable) {
+			names = append(names, name)
+		}
+	}
+	for name := range r.globals {
+		names = append(names, name)
+	}
+	return names
+}
+
+// declaredNames returns the names of the variables, functions, classes and imports declared by statements.
+func declaredNames(statements []ast.Stmt) []string {
+	names := []string{}
+	for _, statement := range statements {
+		switch stmt := statement.(type) {
+		case *ast.Var:
+			names = append(names, stmt.Name.Lexeme)
+		case *ast.Function:
+			names = append(names, stmt.Name.Lexeme)
+		case *ast.Class:
+			names = append(names, stmt.Name.Lexeme)
+		case *ast.Import:
+			if stmt.Alias != nil {
+				names = append(names, stmt.Alias.Lexeme)
+			}
+			for _, name := range stmt.Names {
+				names = append(names, name.Lexeme)
+			}
+		}
+	}
+	return names
+}
+
 func (r *Resolver) resolveFunction(function *ast.Function, functionType types.FunctionType) error {
 	enclosingFunction := r.currentFunction
 	r.currentFunction = functionType
 
 	r.BeginScope()
 	for _, param := range function.Params {
-		err := r.declare(param)
-		if err != nil {
-			return err
-		}
-		err = r.define(param)
-		if err != nil {
-			return err
-		}
+		r.declare(param, kindParameter)
+		r.define(param)
 	}
+	r.checkReachable(function.Body)
 	for _, bodyStmt := range function.Body {
 		if err := r.resolveStmt(bodyStmt); err != nil {
 			return err
@@ -0,0 +1,110 @@
+package resolver_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/interpreter"
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+	"github.com/mejroslav/golox/internal/pkg/golox/parser"
+	"github.com/mejroslav/golox/internal/pkg/golox/resolver"
+	"github.com/mejroslav/golox/internal/pkg/golox/scanner"
+)
+
+// resolve scans, parses and resolves a program and returns its errors and
+// warnings, each as its code followed by its line.
+func resolve(t *testing.T, source string) (errors []string, warnings []string) {
+	t.Helper()
+	codeScanner := scanner.NewCodeScanner(1, "test.lox")
+	tokens, scanErr := codeScanner.Run(source)
+	if scanErr {
+		t.Fatalf("scanning errors: %v", codeScanner.Errors())
+	}
+	parser := parser.NewParser(tokens)
+	statements, parseErr := parser.Parse()
+	if parseErr {
+		t.Fatalf("parsing errors: %v", parser.Errors())
+	}
+	resolver := resolver.NewResolver(interpreter.NewInterpreter())
+	resolver.SetSuppressions(codeScanner.Suppressions())
+	resolver.Resolve(statements)
+	return diagnostics(resolver.Errors()), diagnostics(resolver.Warnings())
+}
+
+func diagnostics(errs []error) []string {
+	result := []string{}
+	for _, err := range errs {
+		diagnostic := err.(lox_error.Diagnosable).Diagnostic()
+		result = append(result, fmt.Sprintf("%s %d", diagnostic.Code, diagnostic.Span.StartLine))
+	}
+	return result
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{
+			name:   "valid program",
+			source: `fun f(a) { return a; } class A { init() { this.x = 1; } } class B < A { init() { super.init(); } } print f(B());`,
+			want:   []string{},
+		},
+		{
+			name:   "top-level return",
+			source: `return 1;`,
+			want:   []string{"E0301 1"},
+		},
+		{
+			name:   "return value from an initializer",
+			source: `class A { init() { return 1; } }`,
+			want:   []string{"E0302 1"},
+		},
+		{
+			name:   "loop control outside of a loop",
+			source: "break;\nfun f() { while (true) { fun g() { continue; } } }",
+			want:   []string{"E0303 1", "E0303 2"},
+		},
+		{
+			name:   "this and super outside of a class",
+			source: "print this;\nprint super.x;",
+			want:   []string{"E0304 1", "E0305 2"},
+		},
+		{
+			name:   "super in a class without a superclass",
+			source: "class A {\n  f() { return super.f(); }\n}\nclass B < A {}",
+			want:   []string{"E0305 2"},
+		},
+		{
+			name:   "self inheritance",
+			source: `class A < A {}`,
+			want:   []string{"E0306 1"},
+		},
+		{
+			name:   "local declared twice",
+			source: `fun f() { var a = 1; var a = 2; print a; }`,
+			want:   []string{"E0103 1"},
+		},
+		{
+			name:   "local read in its own initializer",
+			source: `{ var c = c; }`,
+			want:   []string{"E0104 1"},
+		},
+		{
+			name:   "all errors are reported",
+			source: "return 1;\nbreak;\nprint this;\nclass A < A {}\n{ var c = c; }\ncontinue;",
+			want:   []string{"E0301 1", "E0303 2", "E0304 3", "E0306 4", "E0104 5", "E0303 6"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, _ := resolve(t, test.source)
+			if !slices.Equal(got, test.want) {
+				t.Errorf("errors %v, want %v", got, test.want)
+			}
+		})
+	}
+}
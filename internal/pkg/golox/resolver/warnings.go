@@ -0,0 +1,114 @@
+package resolver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/ast"
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+	"github.com/mejroslav/golox/internal/pkg/golox/token"
+)
+
+// variableKind tells what declared a local variable, for the messages of warnings.
+type variableKind string
+
+const (
+	kindVariable  variableKind = "Local variable"
+	kindParameter variableKind = "Parameter"
+	kindFunction  variableKind = "Local function"
+	kindClass     variableKind = "Local class"
+	kindImport    variableKind = "Imported name"
+)
+
+// variable is the state of a local variable within its scope.
+type variable struct {
+	name        *token.Token   // The declaration, nil for "this" and "super"
+	kind        variableKind   // What declared the variable
+	defined     bool           // Whether the initializer has been resolved
+	read        bool           // Whether the value is ever read
+	assignments []*token.Token // The assignments to the variable after its declaration
+}
+
+// SetSuppressions sets the warnings disabled by "lox:ignore" comments in the resolved code.
+func (r *Resolver) SetSuppressions(suppressions lox_error.Suppressions) {
+	r.suppressions = suppressions
+}
+
+// Warnings returns the warnings found while resolving, in the order of their position.
+func (r *Resolver) Warnings() []error {
+	sort.SliceStable(r.warnings, func(i, j int) bool {
+		a, b := r.warnings[i].(lox_error.Warning).Token, r.warnings[j].(lox_error.Warning).Token
+		return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+	})
+	return r.warnings
+}
+
+// warn records a warning unless a comment suppresses it.
+func (r *Resolver) warn(name token.Token, code lox_error.Code, message string, labels ...lox_error.Label) {
+	if r.suppressions.Suppresses(name.Line, code) {
+		return
+	}
+	r.warnings = append(r.warnings, lox_error.Warning{Token: name, Code: code, Message: message, Labels: labels})
+}
+
+// checkUnused warns about the variables of a scope that are never read.
+// Names starting with '_' are unused on purpose.
+func (r *Resolver) checkUnused(scope map[string]*variable) {
+	for _, v := range scope {
+		if v.name == nil || v.read || strings.HasPrefix(v.name.Lexeme, "_") {
+			continue
+		}
+		if len(v.assignments) == 0 {
+			r.warn(*v.name, lox_error.CodeUnusedVariable, fmt.Sprintf("%s '%s' is never used.", v.kind, v.name.Lexeme))
+			continue
+		}
+
+		labels := []lox_error.Label{}
+		for _, assignment := range v.assignments {
+			labels = append(labels, lox_error.Label{Span: lox_error.TokenSpan(*assignment), Message: "assigned here"})
+		}
+		r.warn(*v.name, lox_error.CodeUnreadAssignment, fmt.Sprintf("%s '%s' is assigned but never read.", v.kind, v.name.Lexeme), labels...)
+	}
+}
+
+// checkShadowing warns if a newly declared local variable hides a variable
+// of an enclosing scope or a global variable.
+func (r *Resolver) checkShadowing(name *token.Token) {
+	for i := r.scopeStack.Size() - 2; i >= 0; i-- {
+		scope, _ := r.scopeStack.Get(i)
+		v, ok := scope.(map[string]*variable)[name.Lexeme]
+		if !ok || v.name == nil {
+			continue
+		}
+		r.warn(*name, lox_error.CodeShadowedVariable, fmt.Sprintf("'%s' shadows a variable of an enclosing scope.", name.Lexeme),
+			lox_error.Label{Span: lox_error.TokenSpan(*v.name), Message: "shadowed declaration"})
+		return
+	}
+
+	if r.globals[name.Lexeme] {
+		r.warn(*name, lox_error.CodeShadowedVariable, fmt.Sprintf("'%s' shadows a global variable.", name.Lexeme))
+	}
+}
+
+// checkReachable warns about statements following a 'return', 'break',
+// 'continue' or 'throw' in the same list of statements.
+func (r *Resolver) checkReachable(statements []ast.Stmt) {
+	for _, statement := range statements[:max(len(statements)-1, 0)] {
+		var keyword *token.Token
+		switch stmt := statement.(type) {
+		case *ast.Return:
+			keyword = stmt.Keyword
+		case *ast.Break:
+			keyword = stmt.Keyword
+		case *ast.Continue:
+			keyword = stmt.Keyword
+		case *ast.Throw:
+			keyword = stmt.Keyword
+		default:
+			continue
+		}
+		r.warn(*keyword, lox_error.CodeUnreachableCode, fmt.Sprintf("Code after '%s' is unreachable.", keyword.Lexeme))
+		return
+	}
+}
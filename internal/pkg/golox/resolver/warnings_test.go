@@ -0,0 +1,130 @@
+package resolver_test
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWarnings(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+		errors []string
+	}{
+		{
+			name:   "no warnings",
+			source: `var g = 1; fun f(a) { var b = a; return b + g; } print f(1);`,
+			want:   []string{},
+		},
+		{
+			name:   "unused local variable and parameter",
+			source: "fun f(a) {\n  var b = 1;\n}",
+			want:   []string{"W0001 1", "W0001 2"},
+		},
+		{
+			name:   "unused local function and class",
+			source: "fun f() {\n  fun g() {}\n  class C {}\n}",
+			want:   []string{"W0001 2", "W0001 3"},
+		},
+		{
+			name:   "names starting with an underscore",
+			source: `fun f(_a) { var _b = 1; }`,
+			want:   []string{},
+		},
+		{
+			name:   "unused globals",
+			source: `var a = 1; fun f() {}`,
+			want:   []string{},
+		},
+		{
+			name:   "assigned but never read",
+			source: "fun f() {\n  var a;\n  a = 1;\n}",
+			want:   []string{"W0002 2"},
+		},
+		{
+			name:   "unreachable code",
+			source: "fun f() {\n  return;\n  print 1;\n}\nwhile (true) {\n  break;\n  print 2;\n}",
+			want:   []string{"W0003 2", "W0003 6"},
+		},
+		{
+			name:   "code after throw and continue",
+			source: "for (;;) {\n  continue;\n  print 1;\n}\nthrow 1;\nprint 2;",
+			want:   []string{"W0003 2", "W0003 5"},
+		},
+		{
+			name:   "shadowed local",
+			source: "fun f() {\n  var a = 1;\n  {\n    var a = 2;\n    print a;\n  }\n  print a;\n}",
+			want:   []string{"W0004 4"},
+		},
+		{
+			name:   "shadowed global",
+			source: "var a = 1;\nfun f(a) {\n  return a;\n}",
+			want:   []string{"W0004 2"},
+		},
+		{
+			name:   "super in a class without a superclass",
+			source: "class A {\n  f() {\n    super.f();\n  }\n}",
+			want:   []string{"W0005 3"},
+			errors: []string{"E0305 3"},
+		},
+		{
+			name:   "super in a subclass",
+			source: "class A { f() {} }\nclass B < A {\n  f() {\n    super.f();\n  }\n}",
+			want:   []string{},
+		},
+		{
+			name:   "ignore on the next line",
+			source: "fun f() {\n  // lox:ignore unused\n  var a = 1;\n  var b = 2;\n}",
+			want:   []string{"W0001 4"},
+		},
+		{
+			name:   "ignore on the same line",
+			source: "fun f() {\n  var a = 1; // lox:ignore W0001\n  var b = 2;\n}",
+			want:   []string{"W0001 3"},
+		},
+		{
+			name:   "ignore several names",
+			source: "var a = 1;\nfun f() {\n  // lox:ignore unused, shadow\n  var a = 2;\n}",
+			want:   []string{},
+		},
+		{
+			name:   "ignore everything",
+			source: "var a = 1;\nfun f() {\n  // lox:ignore\n  var a = 2;\n}",
+			want:   []string{},
+		},
+		{
+			name:   "ignore another warning",
+			source: "fun f() {\n  // lox:ignore shadow\n  var a = 1;\n}",
+			want:   []string{"W0001 3"},
+		},
+		{
+			name:   "ignore super",
+			source: "class A {\n  f() {\n    super.f(); // lox:ignore super\n  }\n}",
+			want:   []string{},
+			errors: []string{"E0305 3"},
+		},
+		{
+			name:   "ignore unreachable code",
+			source: "fun f() {\n  return; // lox:ignore unreachable\n  print 1;\n}",
+			want:   []string{},
+		},
+		{
+			name:   "not an ignore comment",
+			source: "fun f() {\n  // lox:ignored\n  var a = 1;\n}",
+			want:   []string{"W0001 3"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errors, got := resolve(t, test.source)
+			if !slices.Equal(errors, test.errors) {
+				t.Fatalf("errors %v, want %v", errors, test.errors)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("warnings %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package runner
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/peterh/liner"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/interpreter"
+	"github.com/mejroslav/golox/internal/pkg/golox/token"
+)
+
+// errInterrupted is returned by a lineReader when the user cancels the current input with Ctrl-C.
+var errInterrupted = errors.New("interrupted")
+
+// replCommands are the meta-commands offered by tab completion.
+var replCommands = []string{":help", ":reset", ":load", ":env", ":ast", ":quit"}
+
+// lineReader reads the input of the REPL line by line.
+type lineReader interface {
+	// ReadLine prints the prompt and returns the next line without the newline.
+	ReadLine(prompt string) (string, error)
+	// AddHistory records a complete input in the history.
+	AddHistory(entry string)
+	// Close releases the terminal and saves the history.
+	Close() error
+}
+
+// plainReader reads lines without any editing, used when stdin is not a terminal.
+type plainReader struct {
+	input  *bufio.Scanner
+	output io.Writer
+}
+
+func newPlainReader(input io.Reader, output io.Writer) *plainReader {
+	return &plainReader{
+		input:  bufio.NewScanner(input),
+		output: output,
+	}
+}
+
+func (pr *plainReader) ReadLine(prompt string) (string, error) {
+	fmt.Fprint(pr.output, prompt)
+	if !pr.input.Scan() {
+		if err := pr.input.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return pr.input.Text(), nil
+}
+
+func (pr *plainReader) AddHistory(entry string) {}
+
+func (pr *plainReader) Close() error {
+	return nil
+}
+
+// lineEditor reads lines from a terminal with line editing, a persistent
+// history searchable with Ctrl-R and tab completion.
+type lineEditor struct {
+	state       *liner.State
+	historyPath string
+}
+
+// newLineEditor takes over the terminal and loads the history. The complete
+// function returns the candidates for the word that ends at the cursor.
+func newLineEditor(complete func(word string) []string) *lineEditor {
+	state := liner.NewLiner()
+	state.SetCtrlCAborts(true)
+	state.SetTabCompletionStyle(liner.TabPrints)
+	state.SetWordCompleter(func(line string, pos int) (string, []string, string) {
+		start := wordStart(line, pos)
+		return line[:start], complete(line[start:pos]), line[pos:]
+	})
+
+	editor := &lineEditor{state: state, historyPath: historyPath()}
+	if file, err := os.Open(editor.historyPath); err == nil {
+		state.ReadHistory(file)
+		file.Close()
+	}
+	return editor
+}
+
+func (le *lineEditor) ReadLine(prompt string) (string, error) {
+	line, err := le.state.Prompt(prompt)
+	if err == liner.ErrPromptAborted {
+		return "", errInterrupted
+	}
+	return line, err
+}
+
+func (le *lineEditor) AddHistory(entry string) {
+	le.state.AppendHistory(entry)
+}
+
+// Close restores the terminal and writes the history file. Failing to save the history is not an error.
+func (le *lineEditor) Close() error {
+	if le.historyPath != "" && os.MkdirAll(filepath.Dir(le.historyPath), 0o755) == nil {
+		if file, err := os.Create(le.historyPath); err == nil {
+			le.state.WriteHistory(file)
+			file.Close()
+		}
+	}
+	return le.state.Close()
+}
+
+// historyPath returns the path of the history file, $XDG_STATE_HOME/golox/history,
+// or ~/.local/state/golox/history if XDG_STATE_HOME is not set.
+func historyPath() string {
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		stateHome = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(stateHome, "golox", "history")
+}
+
+// isTerminal checks if file is an interactive terminal rather than a pipe or a regular file.
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// wordStart returns the index where the word ending at pos begins. A word may
+// contain dots, so that 'point.x' is completed as a whole, and start with ':'.
+func wordStart(line string, pos int) int {
+	start := pos
+	for start > 0 {
+		c := line[start-1]
+		if !isIdentifierChar(c) && c != '.' {
+			break
+		}
+		start--
+	}
+	if start > 0 && line[start-1] == ':' {
+		start--
+	}
+	return start
+}
+
+func isIdentifierChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// complete returns the completions of word: meta-commands for words starting
+// with ':', properties of the value before the last '.', and keywords and
+// global variables otherwise.
+func (r *Repl) complete(word string) []string {
+	if strings.HasPrefix(word, ":") {
+		return withPrefix(replCommands, word, "")
+	}
+
+	path, partial, found := cutLast(word, ".")
+	if !found {
+		names := make([]string, 0, len(token.Keywords))
+		for keyword := range token.Keywords {
+			names = append(names, keyword)
+		}
+		for name := range r.interpreter.Globals().Values() {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return withPrefix(names, partial, "")
+	}
+
+	// Only follow plain global variables and fields, so that completion never runs any code.
+	parts := strings.Split(path, ".")
+	object, ok := r.interpreter.Globals().Values()[parts[0]]
+	if !ok {
+		return nil
+	}
+	for _, name := range parts[1:] {
+		instance, ok := object.(*interpreter.LoxInstance)
+		if !ok {
+			return nil
+		}
+		if object, ok = instance.Fields[name]; !ok {
+			return nil
+		}
+	}
+	return withPrefix(interpreter.PropertyNames(object), partial, path+".")
+}
+
+// cutLast slices s around the last instance of sep. If sep does not occur, before is empty and after is s.
+func cutLast(s string, sep string) (before string, after string, found bool) {
+	index := strings.LastIndex(s, sep)
+	if index < 0 {
+		return "", s, false
+	}
+	return s[:index], s[index+len(sep):], true
+}
+
+// withPrefix returns head followed by each of the names that start with prefix.
+func withPrefix(names []string, prefix string, head string) []string {
+	var completions []string
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) {
+			completions = append(completions, head+name)
+		}
+	}
+	return completions
+}
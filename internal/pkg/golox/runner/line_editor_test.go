@@ -0,0 +1,62 @@
+package runner
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWordStart(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"print pri", "pri"},
+		{"print point.x", "point.x"},
+		{"print point.", "point."},
+		{":re", ":re"},
+		{"f(a, b_2", "b_2"},
+		{"x + ", ""},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		if got := test.line[wordStart(test.line, len(test.line)):]; got != test.want {
+			t.Errorf("word of %q is %q, want %q", test.line, got, test.want)
+		}
+	}
+}
+
+func TestComplete(t *testing.T) {
+	var output strings.Builder
+	setup := `class Point { init(x) { this.x = x; this.inner = this; } norm() { return 0; } }
+var point = Point(1);
+var number = 1;
+var nums = [1];`
+	repl := NewRepl(strings.NewReader(setup), &output)
+	repl.Run()
+
+	tests := []struct {
+		word string
+		want []string
+	}{
+		{":re", []string{":reset"}},
+		{":", replCommands},
+		{":x", nil},
+		{"nu", []string{"number", "nums"}},
+		{"whi", []string{"while"}},
+		{"cl", []string{"class", "clock"}},
+		{"point.", []string{"point.inner", "point.norm", "point.x"}},
+		{"point.n", []string{"point.norm"}},
+		{"point.inner.x", []string{"point.inner.x"}},
+		{"point.missing.x", nil},
+		{"number.x", nil},
+		{"unknown.x", nil},
+	}
+
+	for _, test := range tests {
+		if got := repl.complete(test.word); !slices.Equal(got, test.want) {
+			t.Errorf("complete(%q) = %q, want %q", test.word, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/ast"
+	"github.com/mejroslav/golox/internal/pkg/golox/ast_printer"
+	"github.com/mejroslav/golox/internal/pkg/golox/interpreter"
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+	"github.com/mejroslav/golox/internal/pkg/golox/parser"
+	"github.com/mejroslav/golox/internal/pkg/golox/resolver"
+	lox_scanner "github.com/mejroslav/golox/internal/pkg/golox/scanner"
+	"github.com/mejroslav/golox/internal/pkg/golox/token"
+)
+
+const replHelp = `Enter Lox statements or expressions. Input continues on the next line
+until all parentheses, brackets and braces are closed. The value of an
+expression statement is printed, and the trailing ';' may be omitted.
+
+Commands:
+  :help         Show this help message
+  :reset        Forget all definitions and start over
+  :load <file>  Run a Lox file in the current session
+  :env          List the global variables
+  :ast <code>   Print the syntax tree of the code without running it
+  :quit, exit   Leave the REPL
+`
+
+// Repl is an interactive session that keeps its interpreter, and so all
+// global definitions, between inputs.
+type Repl struct {
+	interpreter *interpreter.Interpreter
+	input       lineReader
+	output      io.Writer
+	lineNumber  int // Line number of the next input, used in error messages
+}
+
+func NewRepl(input io.Reader, output io.Writer) *Repl {
+	repl := &Repl{
+		input:      newPlainReader(input, output),
+		output:     output,
+		lineNumber: 1,
+	}
+	repl.reset()
+	return repl
+}
+
+// RunPrompt starts a REPL that reads statements from stdin and executes them.
+// On a terminal, lines can be edited and the history is kept between sessions.
+func RunPrompt() {
+	repl := NewRepl(os.Stdin, os.Stdout)
+	if isTerminal(os.Stdin) {
+		repl.input = newLineEditor(repl.complete)
+	}
+	defer repl.input.Close()
+	repl.Run()
+}
+
+// Run reads and executes inputs until the end of input or an exit command.
+func (r *Repl) Run() {
+	fmt.Fprintln(r.output, "Lox REPL. Type ':help' for help and ':quit' to quit.")
+	for {
+		source, ok := r.read()
+		if !ok {
+			fmt.Fprintln(r.output)
+			return
+		}
+
+		line := r.lineNumber
+		r.lineNumber += strings.Count(source, "\n") + 1
+		sources.add("<stdin>", source)
+
+		command := strings.TrimSpace(source)
+		if command == "" {
+			continue
+		}
+		if command == "exit" || command == ":quit" {
+			return
+		}
+		if strings.HasPrefix(command, ":") {
+			r.runCommand(command, line)
+			continue
+		}
+
+		r.eval(source, line)
+	}
+}
+
+// read reads one complete input, asking for more lines while it is unfinished.
+// Ctrl-C discards the unfinished input and starts over.
+func (r *Repl) read() (string, bool) {
+	prompt := "> "
+	var lines []string
+	for {
+		line, err := r.input.ReadLine(prompt)
+		if err == errInterrupted {
+			prompt = "> "
+			lines = nil
+			continue
+		}
+		if err != nil {
+			return "", false
+		}
+		lines = append(lines, line)
+		source := strings.Join(lines, "\n")
+		if strings.HasPrefix(strings.TrimSpace(source), ":") || isComplete(source) {
+			if strings.TrimSpace(source) != "" {
+				r.input.AddHistory(source)
+			}
+			return source, true
+		}
+		prompt = "... "
+	}
+}
+
+// runCommand executes a REPL meta-command such as ':help'.
+func (r *Repl) runCommand(command string, line int) {
+	name, argument, _ := strings.Cut(command, " ")
+	argument = strings.TrimSpace(argument)
+
+	switch name {
+	case ":help":
+		fmt.Fprint(r.output, replHelp)
+	case ":reset":
+		r.reset()
+		fmt.Fprintln(r.output, "Session reset.")
+	case ":load":
+		if argument == "" {
+			fmt.Fprintln(os.Stderr, "Usage: :load <file>")
+			return
+		}
+		source, err := readSource(argument)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return
+		}
+		statements, ok := r.parse(source, argument, 1)
+		if ok {
+			r.execute(statements, false)
+		}
+	case ":env":
+		r.printEnvironment()
+	case ":ast":
+		statements, ok := r.parse(argument, "<stdin>", line)
+		if ok {
+			fmt.Fprint(r.output, ast_printer.NewASTPrinter().Print(statements))
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command '%s'. Type ':help' for help.\n", name)
+	}
+}
+
+// eval scans, parses, resolves and executes one input and prints the values of its expression statements.
+func (r *Repl) eval(source string, line int) {
+	statements, ok := r.parse(source, "<stdin>", line)
+	if ok {
+		r.execute(statements, true)
+	}
+}
+
+// parse scans and parses source and reports any errors.
+//
+// If the input typed at the prompt fails to parse at its very end, it is parsed
+// again with a ';' added, so that expressions and statements can be typed without it.
+func (r *Repl) parse(source string, file string, line int) ([]ast.Stmt, bool) {
+	codeScanner := lox_scanner.NewCodeScanner(line, file)
+	tokens, scanErr := codeScanner.Run(source)
+	if scanErr {
+		reportErrors(codeScanner.Errors())
+		return nil, false
+	}
+
+	codeParser := parser.NewParser(tokens)
+	statements, parseErr := codeParser.Parse()
+	if !parseErr {
+		return statements, true
+	}
+
+	if file == "<stdin>" && len(tokens) > 1 && failedAtEnd(codeParser.Errors()) {
+		last := tokens[len(tokens)-2]
+		semicolon := token.NewToken(token.SEMICOLON, ";", nil, last.File, last.Line, last.Column)
+		retry := append(tokens[:len(tokens)-1:len(tokens)-1], semicolon, tokens[len(tokens)-1])
+		if statements, retryErr := parser.NewParser(retry).Parse(); !retryErr {
+			return statements, true
+		}
+	}
+	reportErrors(codeParser.Errors())
+	return nil, false
+}
+
+// failedAtEnd reports whether the last parsing error is at the end of the input.
+func failedAtEnd(errs []error) bool {
+	if len(errs) == 0 {
+		return false
+	}
+	parserErr, ok := errs[len(errs)-1].(lox_error.ParserError)
+	return ok && parserErr.Token.Type == token.EOF
+}
+
+// execute resolves and runs the statements one by one. If echo is set, the
+// value of every expression statement is printed, unless it is nil.
+func (r *Repl) execute(statements []ast.Stmt, echo bool) {
+	resolver := resolver.NewResolver(r.interpreter)
+	statements, resolveErr := resolver.Resolve(statements)
+	if resolveErr {
+		reportErrors(resolver.Errors())
+		return
+	}
+
+	for _, statement := range statements {
+		value, err := interpret(r.interpreter, []ast.Stmt{statement})
+		if err != nil {
+			reportErrors([]error{err})
+			return
+		}
+		if _, ok := statement.(*ast.Expression); ok && echo && value != nil {
+			fmt.Fprintln(r.output, interpreter.Stringify(value))
+		}
+	}
+}
+
+// printEnvironment lists the global variables in alphabetical order.
+func (r *Repl) printEnvironment() {
+	values := r.interpreter.Globals().Values()
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(r.output, "%s = %s\n", name, interpreter.Stringify(values[name]))
+	}
+}
+
+// reset replaces the interpreter with a fresh one.
+func (r *Repl) reset() {
+	r.interpreter = newInterpreter()
+	r.interpreter.SetStdout(r.output)
+}
+
+// isComplete checks if all parentheses, brackets, braces and strings in source are closed.
+func isComplete(source string) bool {
+	depth := 0
+	inString := false
+	// Depths at which the currently open string interpolations "${" started.
+	interpolations := []int{}
+
+	for i := 0; i < len(source); i++ {
+		c := source[i]
+		if inString {
+			switch {
+			case c == '\\':
+				i++ // Skip the escaped character.
+			case c == '"':
+				inString = false
+			case c == '$' && i+1 < len(source) && source[i+1] == '{':
+				i++
+				interpolations = append(interpolations, depth)
+				depth++
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '/':
+			if i+1 < len(source) && source[i+1] == '/' {
+				// Skip the comment up to the end of the line.
+				for i < len(source) && source[i] != '\n' {
+					i++
+				}
+			}
+		case '(', '[', '{':
+			depth++
+		case ')', ']':
+			depth--
+		case '}':
+			depth--
+			if len(interpolations) > 0 && depth == interpolations[len(interpolations)-1] {
+				interpolations = interpolations[:len(interpolations)-1]
+				inString = true
+			}
+		}
+	}
+
+	return !inString && depth <= 0
+}
@@ -0,0 +1,101 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsComplete(t *testing.T) {
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{`print 1;`, true},
+		{`1 + 2`, true},
+		{`fun f() {`, false},
+		{"fun f() {\n  return 1;\n}", true},
+		{`print (1 +`, false},
+		{`var xs = [1,`, false},
+		{`"unterminated`, false},
+		{"\"multi\nline\"", true},
+		{`"a ( b"`, true},
+		{`"escaped \" quote`, false},
+		{`"${`, false},
+		{`"${ {"k": 1}["k"] }`, false},
+		{`"${ {"k": 1}["k"] }"`, true},
+		{`"${"${x}"}"`, true},
+		{`print 1; // {`, true},
+		{"// (\nprint (", false},
+		{`}`, true},
+	}
+
+	for _, test := range tests {
+		if got := isComplete(test.source); got != test.want {
+			t.Errorf("isComplete(%q) = %v, want %v", test.source, got, test.want)
+		}
+	}
+}
+
+func TestRepl(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []string
+		want  []string // The output after the welcome line, one prompt per line of input
+	}{
+		{
+			name:  "expressions are echoed",
+			input: []string{"1 + 2", `"a" + "b";`, "nil", "var x = 1;", "print x;"},
+			want:  []string{"> 3", `> ab`, "> > > 1", "> "},
+		},
+		{
+			name:  "definitions are kept",
+			input: []string{"fun double(x) { return x * 2; }", "double(21)"},
+			want:  []string{"> > 42", "> "},
+		},
+		{
+			name:  "map literal at the end of a line",
+			input: []string{`var m = {"a": 1}`, "m", `m["b"] = {"c": 2}`, `m["b"]`},
+			want:  []string{`> > {"a": 1}`, `> {"c": 2}`, `> {"c": 2}`, "> "},
+		},
+		{
+			name:  "anonymous function at the end of a line",
+			input: []string{"var f = fun (x) { return x + 1; }", "f(1)", "var g = (x) => x * 2", "g(2)"},
+			want:  []string{"> > 2", "> > 4", "> "},
+		},
+		{
+			name:  "multi-line input",
+			input: []string{"fun f() {", "  return 1;", "}", "f()"},
+			want:  []string{"> ... ... > 1", "> "},
+		},
+		{
+			name:  "environment",
+			input: []string{"var b = 2;", "var a = [1];", ":env"},
+			want:  []string{"> > > Error = <native fn Error>", "a = [1]", "b = 2", "clock = <native fn clock>", "input = <native fn input>", "> "},
+		},
+		{
+			name:  "reset",
+			input: []string{"var a = 1;", ":reset", ":env"},
+			want:  []string{"> > Session reset.", "> Error = <native fn Error>", "clock = <native fn clock>", "input = <native fn input>", "> "},
+		},
+		{
+			name:  "quit",
+			input: []string{"1", ":quit", "2"},
+			want:  []string{"> 1", "> "},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var output strings.Builder
+			NewRepl(strings.NewReader(strings.Join(test.input, "\n")), &output).Run()
+			got, _ := strings.CutPrefix(output.String(), "Lox REPL. Type ':help' for help and ':quit' to quit.\n")
+			want := strings.Join(test.want, "\n")
+			if test.input[len(test.input)-2] != ":quit" {
+				want += "\n"
+			}
+			if got != want {
+				t.Errorf("output\n%q\nwant\n%q", got, want)
+			}
+		})
+	}
+}
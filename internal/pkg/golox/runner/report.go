@@ -0,0 +1,84 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+)
+
+// renderer formats the diagnostics printed to stderr, in color if stderr is a terminal
+// and the NO_COLOR environment variable is not set.
+var renderer = lox_error.Renderer{
+	Source: sources.line,
+	Color:  isTerminal(os.Stderr) && os.Getenv("NO_COLOR") == "",
+}
+
+// diagnosticsFormat is the format of the diagnostics printed to stderr:
+// "human" for rendered text, "json" for one JSON object per line.
+var diagnosticsFormat = "human"
+
+// SetDiagnosticsFormat selects how errors are printed, either "human" or "json".
+func SetDiagnosticsFormat(format string) error {
+	switch format {
+	case "human", "json":
+		diagnosticsFormat = format
+		return nil
+	}
+	return fmt.Errorf("invalid diagnostics format: %s", format)
+}
+
+// warningsEnabled tells whether the warnings of the resolver are printed before a program runs.
+var warningsEnabled = false
+
+// SetWarnings enables or disables printing the warnings of the resolver when running files.
+func SetWarnings(enabled bool) {
+	warningsEnabled = enabled
+}
+
+// reportedError is returned after the diagnostics of a failed run have been printed.
+// Its message only summarizes them.
+type reportedError string
+
+func (e reportedError) Error() string {
+	return string(e)
+}
+
+// IsReported reports whether the details of err have already been printed as diagnostics.
+func IsReported(err error) bool {
+	var reported reportedError
+	return errors.As(err, &reported)
+}
+
+// reportDiagnostic prints err to stderr in the selected format, if it is a
+// diagnostic. It reports whether the error was printed.
+func reportDiagnostic(err error) bool {
+	var diagnosable lox_error.Diagnosable
+	if !errors.As(err, &diagnosable) {
+		return false
+	}
+
+	diagnostic := diagnosable.Diagnostic()
+	if diagnosticsFormat == "json" {
+		data, err := json.Marshal(diagnostic)
+		if err != nil {
+			return false
+		}
+		fmt.Fprintln(os.Stderr, string(data))
+		return true
+	}
+
+	fmt.Fprintln(os.Stderr, renderer.Render(diagnostic))
+	return true
+}
+
+// reportErrors prints all errors found by the scanner, the parser or the resolver, or the warnings of the resolver.
+func reportErrors(errs []error) {
+	for _, err := range errs {
+		if !reportDiagnostic(err) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	}
+}
@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"runtime/debug"
 
+	"github.com/mejroslav/golox/internal/pkg/golox/ast"
 	"github.com/mejroslav/golox/internal/pkg/golox/ast_printer"
 	"github.com/mejroslav/golox/internal/pkg/golox/interpreter"
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
 	"github.com/mejroslav/golox/internal/pkg/golox/parser"
 	"github.com/mejroslav/golox/internal/pkg/golox/resolver"
 	lox_scanner "github.com/mejroslav/golox/internal/pkg/golox/scanner"
@@ -17,28 +20,17 @@ import (
 func RunFile(path string, showTokens bool, showAST bool) error {
 
 	slog.Debug("Running file", "path", path)
-	file, err := os.Open(path)
+	source, err := readSource(path)
 	if err != nil {
-		return fmt.Errorf("could not open file: %w", err)
-	}
-	defer file.Close()
-
-	var source string
-
-	// Load the entire file in memory
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		source += scanner.Text() + "\n"
-	}
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("error reading file: %w", err)
+		return err
 	}
 
 	// Run the code scanner on the loaded source
 	codeScanner := lox_scanner.NewCodeScanner(1, path)
 	tokens, scanErr := codeScanner.Run(source)
 	if scanErr {
-		return fmt.Errorf("scanning errors")
+		reportErrors(codeScanner.Errors())
+		return reportedError("scanning errors")
 	}
 
 	if showTokens {
@@ -53,7 +45,8 @@ func RunFile(path string, showTokens bool, showAST bool) error {
 	parser := parser.NewParser(tokens)
 	statements, parseErr := parser.Parse()
 	if parseErr {
-		return fmt.Errorf("parsing errors")
+		reportErrors(parser.Errors())
+		return reportedError("parsing errors")
 	}
 
 	if showAST {
@@ -64,38 +57,146 @@ func RunFile(path string, showTokens bool, showAST bool) error {
 	}
 
 	// Resolve the statements
-	interpreter := interpreter.NewInterpreter()
+	interpreter := newInterpreter()
 	resolver := resolver.NewResolver(interpreter)
-	statements, err = resolver.Resolve(statements)
-	if err != nil {
-		return fmt.Errorf("%w", err)
+	resolver.SetSuppressions(codeScanner.Suppressions())
+	statements, resolveErr := resolver.Resolve(statements)
+	if resolveErr {
+		reportErrors(resolver.Errors())
+		return reportedError("resolving errors")
+	}
+	if warningsEnabled {
+		reportErrors(resolver.Warnings())
 	}
 
 	// Interpret the statements
-	_, runtimeErr := interpreter.Interpret(statements)
+	exit, err := interpreter.EnterMainFile(path, statements)
+	if err != nil {
+		return err
+	}
+	defer exit()
+	_, runtimeErr := interpret(interpreter, statements)
 	if runtimeErr != nil {
+		if reportDiagnostic(runtimeErr) {
+			return reportedError("runtime error")
+		}
 		return fmt.Errorf("%w", runtimeErr)
 	}
 	return nil
 }
 
-// TODO: Fix the error handling and reporting in the REPL
-// RunPrompt starts a REPL that reads lines from stdin and echoes them back.
-func RunPrompt() {
-	scanner := bufio.NewScanner(os.Stdin)
-	lineNumber := 1
-	fmt.Println("Lox REPL. Type 'exit' to quit.")
-	for {
-		fmt.Print("> ")
-		if !scanner.Scan() {
-			break
+// maxCallDepth is the limit of nested calls of the interpreters created by the runner.
+var maxCallDepth = interpreter.DefaultMaxCallDepth
+
+// SetMaxCallDepth sets the limit of nested calls before a stack overflow error, 0 for no limit.
+func SetMaxCallDepth(depth int) {
+	maxCallDepth = depth
+}
+
+// limits are the execution budgets of the interpreters created by the runner.
+var limits interpreter.Limits
+
+// SetLimits sets the execution budgets of programs, see interpreter.Limits.
+func SetLimits(l interpreter.Limits) {
+	limits = l
+}
+
+// newInterpreter returns an interpreter configured with the settings of the runner.
+func newInterpreter() *interpreter.Interpreter {
+	interpreter := interpreter.NewInterpreter()
+	interpreter.SetModuleLoader(moduleLoader(interpreter))
+	interpreter.SetMaxCallDepth(maxCallDepth)
+	interpreter.SetLimits(limits)
+	return interpreter
+}
+
+// interpret runs the statements. A Go panic is a bug of the interpreter, so it is
+// turned into an internal error at the statement being executed and reported like
+// other errors, which also lets the REPL go on.
+func interpret(interpreter *interpreter.Interpreter, statements []ast.Stmt) (value any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Debug("Recovered from panic", "panic", r, "stack", string(debug.Stack()))
+			position, _ := interpreter.Position()
+			interpreter.Unwind()
+			err = lox_error.InternalError{Token: position, Message: fmt.Sprint(r)}
 		}
-		line := scanner.Text()
-		if line == "exit" {
-			break
+	}()
+	return interpreter.Interpret(statements)
+}
+
+// readSource loads the entire file in memory.
+func readSource(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("could not open file: %w", err)
+	}
+	defer file.Close()
+
+	var source string
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		source += scanner.Text() + "\n"
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("error reading file: %w", err)
+	}
+	sources[path] = nil
+	sources.add(path, source)
+	return source, nil
+}
+
+// CheckFile scans, parses and resolves a file without running it, and reports
+// its errors and warnings. It returns the number of warnings.
+func CheckFile(path string) (int, error) {
+	slog.Debug("Checking file", "path", path)
+	_, warnings, err := loadFile(newInterpreter(), path)
+	reportErrors(warnings)
+	return len(warnings), err
+}
+
+// moduleLoader returns a function that scans, parses and resolves imported modules
+// for the given interpreter.
+func moduleLoader(interpreter *interpreter.Interpreter) interpreter.ModuleLoader {
+	return func(path string) ([]ast.Stmt, error) {
+		slog.Debug("Loading module", "path", path)
+		statements, warnings, err := loadFile(interpreter, path)
+		if warningsEnabled {
+			reportErrors(warnings)
 		}
-		codeScanner := lox_scanner.NewCodeScanner(lineNumber, "<stdin>")
-		codeScanner.Run(line)
-		lineNumber++
+		return statements, err
+	}
+}
+
+// loadFile scans, parses and resolves a file for the given interpreter and
+// reports its errors. It returns the statements and the warnings of the resolver.
+func loadFile(interpreter *interpreter.Interpreter, path string) ([]ast.Stmt, []error, error) {
+	source, err := readSource(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	codeScanner := lox_scanner.NewCodeScanner(1, path)
+	tokens, scanErr := codeScanner.Run(source)
+	if scanErr {
+		reportErrors(codeScanner.Errors())
+		return nil, nil, reportedError("scanning errors")
+	}
+
+	parser := parser.NewParser(tokens)
+	statements, parseErr := parser.Parse()
+	if parseErr {
+		reportErrors(parser.Errors())
+		return nil, nil, reportedError("parsing errors")
+	}
+
+	resolver := resolver.NewResolver(interpreter)
+	resolver.SetSuppressions(codeScanner.Suppressions())
+	statements, resolveErr := resolver.Resolve(statements)
+	if resolveErr {
+		reportErrors(resolver.Errors())
+		return nil, resolver.Warnings(), reportedError("resolving errors")
 	}
+	return statements, resolver.Warnings(), nil
 }
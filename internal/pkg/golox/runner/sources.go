@@ -0,0 +1,24 @@
+package runner
+
+import "strings"
+
+// sourceCache keeps the lines of all source files read by the runner, so that
+// error messages can show the code at which an error occurred.
+type sourceCache map[string][]string
+
+// sources holds the source of every file that was run or imported, and of the REPL input as "<stdin>".
+var sources = sourceCache{}
+
+// add appends the lines of source to the lines already known for file.
+func (sc sourceCache) add(file string, source string) {
+	sc[file] = append(sc[file], strings.Split(strings.TrimSuffix(source, "\n"), "\n")...)
+}
+
+// line returns the text of the given 1-based line of file.
+func (sc sourceCache) line(file string, line int) (string, bool) {
+	lines, ok := sc[file]
+	if !ok || line < 1 || line > len(lines) {
+		return "", false
+	}
+	return lines[line-1], true
+}
@@ -4,23 +4,49 @@ import (
 	"fmt"
 	"log/slog"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
 	"github.com/mejroslav/golox/internal/pkg/golox/token"
 )
 
 type CodeScanner struct {
-	source  string
-	tokens  []token.Token
-	start   int
-	current int
-	line    int
-	column  int
-	file    string
+	source   string
+	tokens   []token.Token
+	start    int
+	current  int
+	line     int
+	column   int
+	file     string
+	hadError bool
+	errors   []error // Errors found so far, reported by the caller
+
+	// tokenStart is the position of the first character of the current lexeme.
+	tokenStart position
+
+	// suppressions
+	// suppressions holds the warnings disabled by "// lox:ignore" comments.
+	suppressions lox_error.Suppressions
+
+	// interpolations holds every string interpolation "${...}" we are currently inside of.
+	interpolations []interpolation
+}
+
+// position is the line and column of a character in the source.
+type position struct {
+	line   int
+	column int
+}
+
+// interpolation is a string interpolation "${...}" being scanned.
+type interpolation struct {
+	quote  position // The opening quote of the string containing the interpolation
+	braces int      // The number of unclosed braces within the interpolation
 }
 
 func NewCodeScanner(line int, file string) *CodeScanner {
-	return &CodeScanner{line: line, file: file}
+	return &CodeScanner{line: line, file: file, suppressions: lox_error.Suppressions{}}
 }
 
 // Run scans the provided source code and prints the tokens. It returns true if scanning was successful, false otherwise.
@@ -30,21 +56,31 @@ func (s *CodeScanner) Run(source string) ([]token.Token, bool) {
 	s.start = 0
 	s.current = 0
 	s.tokens = []token.Token{}
+	s.hadError = false
+	s.errors = nil
+	s.suppressions = lox_error.Suppressions{}
+	s.interpolations = nil
 	slog.Debug("Starting scan", "file", s.file, "length", len(s.source))
 	return s.ScanTokens()
 }
 
 func (s *CodeScanner) ScanTokens() ([]token.Token, bool) {
-	hadError := false
 	for !s.isAtEnd() {
 		// We are at the beginning of the next lexeme.
 		s.start = s.current
+		s.tokenStart = position{s.line, s.column + 1}
 		s.scanToken()
 	}
 
+	if len(s.interpolations) > 0 {
+		quote := s.interpolations[0].quote
+		s.reportErrorAt(quote, quote, lox_error.CodeUnterminatedString, "Unterminated string interpolation.")
+	}
+
+	s.start = s.current
 	s.addToken(token.EOF)
 
-	return s.tokens, hadError
+	return s.tokens, s.hadError
 }
 
 func (s *CodeScanner) scanToken() {
@@ -57,23 +93,86 @@ func (s *CodeScanner) scanToken() {
 	case ')':
 		s.addToken(token.RIGHT_PAREN)
 	case '{':
+		if len(s.interpolations) > 0 {
+			s.interpolations[len(s.interpolations)-1].braces++
+		}
 		s.addToken(token.LEFT_BRACE)
 	case '}':
+		if len(s.interpolations) > 0 && s.interpolations[len(s.interpolations)-1].braces == 0 {
+			// This brace closes a "${" inside a string, so the string continues.
+			quote := s.interpolations[len(s.interpolations)-1].quote
+			s.interpolations = s.interpolations[:len(s.interpolations)-1]
+			s.string(quote)
+			return
+		}
+		if len(s.interpolations) > 0 {
+			s.interpolations[len(s.interpolations)-1].braces--
+		}
 		s.addToken(token.RIGHT_BRACE)
+	case '[':
+		s.addToken(token.LEFT_BRACKET)
+	case ']':
+		s.addToken(token.RIGHT_BRACKET)
 	case ',':
 		s.addToken(token.COMMA)
+	case ':':
+		s.addToken(token.COLON)
 	case '.':
 		s.addToken(token.DOT)
-	case '-':
-		s.addToken(token.MINUS)
-	case '+':
-		s.addToken(token.PLUS)
 	case ';':
 		s.addToken(token.SEMICOLON)
-	case '*':
-		s.addToken(token.STAR)
+	case '&':
+		s.addToken(token.AMPERSAND)
+	case '|':
+		s.addToken(token.PIPE)
+	case '^':
+		s.addToken(token.CARET)
 
 	// Operators with one or two characters.
+	case '-':
+		if s.match('-') {
+			s.addToken(token.MINUS_MINUS)
+		} else if s.match('=') {
+			s.addToken(token.MINUS_EQUAL)
+		} else {
+			s.addToken(token.MINUS)
+		}
+	case '+':
+		if s.match('+') {
+			s.addToken(token.PLUS_PLUS)
+		} else if s.match('=') {
+			s.addToken(token.PLUS_EQUAL)
+		} else {
+			s.addToken(token.PLUS)
+		}
+	case '*':
+		if s.match('*') {
+			s.addToken(token.STAR_STAR)
+		} else if s.match('=') {
+			s.addToken(token.STAR_EQUAL)
+		} else {
+			s.addToken(token.STAR)
+		}
+	case '?':
+		if s.match('?') {
+			s.addToken(token.QUESTION_QUESTION)
+		} else if s.match('.') {
+			s.addToken(token.QUESTION_DOT)
+		} else {
+			s.addToken(token.QUESTION)
+		}
+	case '~':
+		if s.match('/') {
+			s.addToken(token.TILDE_SLASH)
+		} else {
+			s.addToken(token.TILDE)
+		}
+	case '%':
+		if s.match('=') {
+			s.addToken(token.PERCENT_EQUAL)
+		} else {
+			s.addToken(token.PERCENT)
+		}
 	case '!':
 		if s.match('=') {
 			s.addToken(token.BANG_EQUAL)
@@ -83,18 +182,24 @@ func (s *CodeScanner) scanToken() {
 	case '=':
 		if s.match('=') {
 			s.addToken(token.EQUAL_EQUAL)
+		} else if s.match('>') {
+			s.addToken(token.ARROW)
 		} else {
 			s.addToken(token.EQUAL)
 		}
 	case '<':
 		if s.match('=') {
 			s.addToken(token.LESS_EQUAL)
+		} else if s.match('<') {
+			s.addToken(token.LESS_LESS)
 		} else {
 			s.addToken(token.LESS)
 		}
 	case '>':
 		if s.match('=') {
 			s.addToken(token.GREATER_EQUAL)
+		} else if s.match('>') {
+			s.addToken(token.GREATER_GREATER)
 		} else {
 			s.addToken(token.GREATER)
 		}
@@ -105,13 +210,16 @@ func (s *CodeScanner) scanToken() {
 			for s.peek() != '\n' && !s.isAtEnd() {
 				s.advance()
 			}
+			s.comment(s.source[s.start+2 : s.current])
+		} else if s.match('=') {
+			s.addToken(token.SLASH_EQUAL)
 		} else {
 			s.addToken(token.SLASH)
 		}
 
 	// String literals.
 	case '"':
-		s.string()
+		s.string(s.tokenStart)
 
 	// Ignore whitespace.
 	case ' ', '\r', '\t':
@@ -131,15 +239,7 @@ func (s *CodeScanner) scanToken() {
 			s.identifier()
 		} else {
 			// Unexpected character.
-			err := lox_error.ScannerError{
-				File:   s.file,
-				Line:   s.line,
-				Column: s.column,
-
-				Context: s.getContextLines(),
-				Message: fmt.Sprintf("Unexpected character '%c'.", c),
-			}
-			fmt.Println(err.Error())
+			s.reportError(lox_error.CodeUnexpectedCharacter, fmt.Sprintf("Unexpected character '%c'.", c))
 		}
 	}
 }
@@ -156,65 +256,178 @@ func (s *CodeScanner) addTokenWithValue(tokenType token.TokenType, value any) {
 
 // string handles string literals, consuming characters until the closing quote is found.
 //
-// It also supports multi-line strings.
-func (s *CodeScanner) string() {
+// It also supports multi-line strings, escape sequences and interpolation. When "${" is
+// found, the text so far is emitted as an INTERPOLATION token and scanning returns to
+// ordinary tokens until the matching "}", after which the string continues. The string
+// "a ${x} b" thus becomes INTERPOLATION("a ") IDENTIFIER(x) STRING(" b"). The quote is
+// the position of the opening quote of the string, where it is reported if unterminated.
+func (s *CodeScanner) string(quote position) {
+	var value strings.Builder
 	for s.peek() != '"' && !s.isAtEnd() {
-		if s.peek() == '\n' {
+		c := s.advance()
+		switch c {
+		case '\n':
 			// Strings can span multiple lines, so we need to increment the line counter.
 			s.newLine()
+			value.WriteByte('\n')
+		case '\\':
+			s.escape(&value)
+		case '$':
+			if s.match('{') {
+				s.addTokenWithValue(token.INTERPOLATION, value.String())
+				s.interpolations = append(s.interpolations, interpolation{quote: quote})
+				return
+			}
+			value.WriteByte('$')
+		default:
+			// Copy the raw byte, so that UTF-8 encoded text is kept intact.
+			value.WriteByte(s.source[s.current-1])
 		}
-		s.advance()
 	}
 
 	if s.isAtEnd() {
-		err := lox_error.ScannerError{
-			File:    s.file,
-			Line:    s.line,
-			Context: s.getContextLines(),
-			Message: "Unterminated string.",
-		}
-		fmt.Println(err.Error())
+		// The rest of the source is in the string, so the interpolations around it are not reported too.
+		s.interpolations = nil
+		s.reportErrorAt(quote, quote, lox_error.CodeUnterminatedString, "Unterminated string.")
 		return
 	} else {
 		// The closing ".
 		s.advance()
 	}
 
-	// Trim the surrounding quotes.
-	value := s.source[s.start+1 : s.current-1]
-	s.addTokenWithValue(token.STRING, value)
+	s.addTokenWithValue(token.STRING, value.String())
 }
 
-// number handles numeric literals. It supports both integer and floating-point numbers.
+// escape handles an escape sequence in a string literal. The backslash has already been consumed.
+func (s *CodeScanner) escape(value *strings.Builder) {
+	backslash := position{s.line, s.column}
+	if s.isAtEnd() {
+		s.reportErrorAt(backslash, backslash, lox_error.CodeInvalidEscape, "Unterminated escape sequence.")
+		return
+	}
+
+	c := s.advance()
+	switch c {
+	case 'n':
+		value.WriteByte('\n')
+	case 't':
+		value.WriteByte('\t')
+	case 'r':
+		value.WriteByte('\r')
+	case '0':
+		value.WriteByte(0)
+	case '"', '\\', '$':
+		value.WriteRune(c)
+	case 'u':
+		s.unicodeEscape(backslash, value)
+	default:
+		s.reportErrorAt(backslash, s.position(), lox_error.CodeInvalidEscape, fmt.Sprintf("Invalid escape sequence '\\%c'.", c))
+	}
+}
+
+// unicodeEscape handles the escape sequences \uXXXX and \u{X...} with up to six hex digits.
+func (s *CodeScanner) unicodeEscape(backslash position, value *strings.Builder) {
+	var digits string
+	if s.match('{') {
+		start := s.current
+		for s.isHexDigit(s.peek()) {
+			s.advance()
+		}
+		digits = s.source[start:s.current]
+		if !s.match('}') || len(digits) == 0 || len(digits) > 6 {
+			s.reportErrorAt(backslash, s.position(), lox_error.CodeInvalidEscape, "Invalid unicode escape sequence, expected '\\u{X}' with 1 to 6 hex digits.")
+			return
+		}
+	} else {
+		start := s.current
+		for i := 0; i < 4 && s.isHexDigit(s.peek()); i++ {
+			s.advance()
+		}
+		digits = s.source[start:s.current]
+		if len(digits) != 4 {
+			s.reportErrorAt(backslash, s.position(), lox_error.CodeInvalidEscape, "Invalid unicode escape sequence, expected '\\uXXXX' with 4 hex digits.")
+			return
+		}
+	}
+
+	codePoint, _ := strconv.ParseUint(digits, 16, 32)
+	if !utf8.ValidRune(rune(codePoint)) {
+		s.reportErrorAt(backslash, s.position(), lox_error.CodeInvalidEscape, fmt.Sprintf("Invalid unicode code point U+%s.", strings.ToUpper(digits)))
+		return
+	}
+	value.WriteRune(rune(codePoint))
+}
+
+// number handles numeric literals. It supports integers and floating-point numbers
+// with an optional exponent (1.5e-3), hexadecimal (0xFF) and binary (0b1010) integers,
+// and underscores as digit separators (1_000_000).
 func (s *CodeScanner) number() {
-	for s.isDigit(s.peek()) {
+	first := s.source[s.start]
+	if first == '0' && (s.peek() == 'x' || s.peek() == 'X') {
 		s.advance()
+		s.radixNumber(16, s.isHexDigit)
+		return
+	}
+	if first == '0' && (s.peek() == 'b' || s.peek() == 'B') {
+		s.advance()
+		s.radixNumber(2, s.isBinaryDigit)
+		return
 	}
 
+	s.digits(s.isDigit)
+
 	if s.peek() == '.' && s.isDigit(s.peekNext()) {
 		// Consume the "."
 		s.advance()
+		s.digits(s.isDigit)
+	}
 
-		for s.isDigit(s.peek()) {
+	if s.peek() == 'e' || s.peek() == 'E' {
+		next := s.peekNext()
+		if s.isDigit(next) || ((next == '+' || next == '-') && s.current+2 < len(s.source) && s.isDigit(s.charAt(s.current+2))) {
+			// Consume the "e" and the sign of the exponent.
 			s.advance()
+			if next == '+' || next == '-' {
+				s.advance()
+			}
+			s.digits(s.isDigit)
 		}
-
 	}
 
-	value, err := strconv.ParseFloat(s.source[s.start:s.current], 64)
+	text := strings.ReplaceAll(s.source[s.start:s.current], "_", "")
+	value, err := strconv.ParseFloat(text, 64)
 	if err != nil {
-		err := lox_error.ScannerError{
-			File:    s.file,
-			Line:    s.line,
-			Context: s.getContextLines(),
-			Message: "Invalid number format: " + err.Error(),
-		}
-		fmt.Println(err.Error())
+		s.reportError(lox_error.CodeInvalidNumber, "Invalid number format: "+err.Error())
 		return
 	}
 	s.addTokenWithValue(token.NUMBER, value)
 }
 
+// radixNumber handles the digits of a hexadecimal or binary literal after its prefix.
+func (s *CodeScanner) radixNumber(base int, isDigit func(rune) bool) {
+	if !isDigit(s.peek()) {
+		s.reportError(lox_error.CodeInvalidNumber, fmt.Sprintf("Expect digits after '%s'.", s.source[s.start:s.current]))
+		return
+	}
+	start := s.current
+	s.digits(isDigit)
+
+	text := strings.ReplaceAll(s.source[start:s.current], "_", "")
+	value, err := strconv.ParseUint(text, base, 64)
+	if err != nil {
+		s.reportError(lox_error.CodeInvalidNumber, "Invalid number format: "+err.Error())
+		return
+	}
+	s.addTokenWithValue(token.NUMBER, float64(value))
+}
+
+// digits consumes a sequence of digits. A single underscore is allowed between two digits.
+func (s *CodeScanner) digits(isDigit func(rune) bool) {
+	for isDigit(s.peek()) || (s.peek() == '_' && isDigit(s.peekNext())) {
+		s.advance()
+	}
+}
+
 // identifier handles identifiers and keywords.
 func (s *CodeScanner) identifier() {
 	for s.isAlphaNumeric(s.peek()) {
@@ -234,6 +447,14 @@ func (s *CodeScanner) isDigit(c rune) bool {
 	return c >= '0' && c <= '9'
 }
 
+func (s *CodeScanner) isBinaryDigit(c rune) bool {
+	return c == '0' || c == '1'
+}
+
+func (s *CodeScanner) isHexDigit(c rune) bool {
+	return s.isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
 func (s *CodeScanner) isAlpha(c rune) bool {
 	return (c >= 'a' && c <= 'z') ||
 		(c >= 'A' && c <= 'Z') ||
@@ -298,72 +519,55 @@ func (s *CodeScanner) isAtEnd() bool {
 	return s.current >= len(s.source)
 }
 
-// getContextLines returns a string with the lines around the current line for error reporting.
-func (s *CodeScanner) getContextLines() string {
-
-	current := s.current
-	output := ""
-	linesBeforeError := 1
-	linesAfterError := 2
-	charsBeforeError := 0
-	charsAfterError := -1
-
-	// Get lines before the current line.
-	lines := 0
-	for i := current - 1; i >= 0; i-- {
-		c := s.charAt(i)
-		if c == '\n' {
-			lines++
-			if lines > linesBeforeError {
-				break
-			}
-		}
-		if lines == 0 {
-			charsBeforeError++
-		}
-		output = string(c) + output
+// comment records the warnings suppressed by a "lox:ignore" comment. The comment
+// applies to its own line if it follows code, otherwise to the next line.
+func (s *CodeScanner) comment(text string) {
+	names, ok := strings.CutPrefix(strings.TrimSpace(text), "lox:ignore")
+	if !ok || (names != "" && names[0] != ' ' && names[0] != '\t') {
+		return
 	}
 
-	// Get lines after the current line.
-	markerPlaced := false
-	lines = 0
-	for i := current; i < len(s.source); i++ {
-		if s.isAtEnd() {
-			break
-		}
-		c := s.charAt(i)
-		if c == '\n' {
-			lines++
-			if lines == 1 {
-				output += marker(charsBeforeError, charsAfterError)
-				markerPlaced = true
-				charsAfterError = 0
-			}
-			if lines > linesAfterError {
-				break
-			}
-		} else {
-			charsAfterError++
-		}
-		output += string(c)
+	line := s.line + 1
+	if len(s.tokens) > 0 && s.tokens[len(s.tokens)-1].Line == s.line {
+		line = s.line
 	}
+	s.suppressions[line] = append(s.suppressions[line], strings.FieldsFunc(names, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})...)
+}
 
-	if !markerPlaced {
-		output += marker(charsBeforeError, charsAfterError)
-	}
+// position returns the position of the last consumed character.
+func (s *CodeScanner) position() position {
+	return position{s.line, s.column}
+}
 
-	return output
+// reportError records a scanning error at the current lexeme and marks the scan as failed.
+func (s *CodeScanner) reportError(code lox_error.Code, message string) {
+	s.reportErrorAt(s.tokenStart, s.position(), code, message)
 }
 
-func marker(before int, after int) string {
-	before = max(before, 1)
-	s := make([]rune, before+after+1)
-	for i := 0; i < before-1; i++ {
-		s[i] = '-'
-	}
-	s[before-1] = '^'
-	for i := before; i < len(s); i++ {
-		s[i] = '-'
+// reportErrorAt records a scanning error spanning the characters from start to end
+// and marks the scan as failed.
+func (s *CodeScanner) reportErrorAt(start position, end position, code lox_error.Code, message string) {
+	err := lox_error.ScannerError{
+		File:      s.file,
+		Line:      start.line,
+		Column:    start.column,
+		EndLine:   end.line,
+		EndColumn: end.column,
+		Code:      code,
+		Message:   message,
 	}
-	return "\n" + string(s)
+	s.errors = append(s.errors, err)
+	s.hadError = true
+}
+
+// Suppressions returns the warnings disabled by the comments of the last scan.
+func (s *CodeScanner) Suppressions() lox_error.Suppressions {
+	return s.suppressions
+}
+
+// Errors returns the errors found by the last scan.
+func (s *CodeScanner) Errors() []error {
+	return s.errors
 }
@@ -0,0 +1,181 @@
+package scanner_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+	"github.com/mejroslav/golox/internal/pkg/golox/scanner"
+)
+
+// scan returns the tokens of the source without the final EOF, each as its type
+// followed by its literal value or lexeme, and the codes of the scanning errors.
+func scan(source string) ([]string, []lox_error.Code) {
+	codeScanner := scanner.NewCodeScanner(1, "test.lox")
+	tokens, _ := codeScanner.Run(source)
+
+	result := []string{}
+	for _, t := range tokens[:len(tokens)-1] {
+		switch literal := t.Literal.(type) {
+		case string:
+			result = append(result, fmt.Sprintf("%s %q", t.Type, literal))
+		case float64:
+			result = append(result, fmt.Sprintf("%s %v", t.Type, literal))
+		default:
+			result = append(result, fmt.Sprintf("%s %s", t.Type, t.Lexeme))
+		}
+	}
+	codes := []lox_error.Code{}
+	for _, err := range codeScanner.Errors() {
+		codes = append(codes, err.(lox_error.ScannerError).Code)
+	}
+	return result, codes
+}
+
+type scanTest struct {
+	name   string
+	source string
+	want   []string
+	codes  []lox_error.Code
+}
+
+func runScanTests(t *testing.T, tests []scanTest) {
+	t.Helper()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, codes := scan(test.source)
+			if test.codes == nil {
+				test.codes = []lox_error.Code{}
+			}
+			if !slices.Equal(codes, test.codes) {
+				t.Errorf("errors %v, want %v", codes, test.codes)
+			}
+			if test.want != nil && !slices.Equal(got, test.want) {
+				t.Errorf("tokens %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestStrings(t *testing.T) {
+	runScanTests(t, []scanTest{
+		{
+			name:   "plain",
+			source: `"hello"`,
+			want:   []string{`STRING "hello"`},
+		},
+		{
+			name:   "multi-line",
+			source: "\"a\nb\"",
+			want:   []string{`STRING "a\nb"`},
+		},
+		{
+			name:   "escape sequences",
+			source: `"\n\t\r\0\"\\\$"`,
+			want:   []string{`STRING "\n\t\r\x00\"\\$"`},
+		},
+		{
+			name:   "unicode escapes",
+			source: `"é\u{1F600}"`,
+			want:   []string{`STRING "é😀"`},
+		},
+		{
+			name:   "dollar without a brace",
+			source: `"$5"`,
+			want:   []string{`STRING "$5"`},
+		},
+		{
+			name:   "interpolation",
+			source: `"a ${x} b"`,
+			want:   []string{`INTERPOLATION "a "`, "IDENTIFIER x", `STRING " b"`},
+		},
+		{
+			name:   "interpolation with braces",
+			source: `"${ {"k": 1}["k"] }!"`,
+			want: []string{`INTERPOLATION ""`, "LEFT_BRACE {", `STRING "k"`, "COLON :", "NUMBER 1", "RIGHT_BRACE }",
+				"LEFT_BRACKET [", `STRING "k"`, "RIGHT_BRACKET ]", `STRING "!"`},
+		},
+		{
+			name:   "nested interpolation",
+			source: `"a${"b${c}"}"`,
+			want:   []string{`INTERPOLATION "a"`, `INTERPOLATION "b"`, "IDENTIFIER c", `STRING ""`, `STRING ""`},
+		},
+		{
+			name:   "escaped interpolation",
+			source: `"\${x}"`,
+			want:   []string{`STRING "${x}"`},
+		},
+		{
+			name:   "unterminated",
+			source: `"abc`,
+			codes:  []lox_error.Code{lox_error.CodeUnterminatedString},
+		},
+		{
+			name:   "invalid escape",
+			source: `"\q"`,
+			codes:  []lox_error.Code{lox_error.CodeInvalidEscape},
+		},
+		{
+			name:   "short unicode escape",
+			source: `"\u12"`,
+			codes:  []lox_error.Code{lox_error.CodeInvalidEscape},
+		},
+		{
+			name:   "invalid code point",
+			source: `"\u{D800}"`,
+			codes:  []lox_error.Code{lox_error.CodeInvalidEscape},
+		},
+	})
+}
+
+func TestNumbers(t *testing.T) {
+	runScanTests(t, []scanTest{
+		{name: "integer", source: "42", want: []string{"NUMBER 42"}},
+		{name: "fraction", source: "3.25", want: []string{"NUMBER 3.25"}},
+		{name: "exponent", source: "1e9 1.5e-3 2E+2", want: []string{"NUMBER 1e+09", "NUMBER 0.0015", "NUMBER 200"}},
+		{name: "hexadecimal", source: "0xFF 0Xa_b", want: []string{"NUMBER 255", "NUMBER 171"}},
+		{name: "binary", source: "0b1010 0B1_1", want: []string{"NUMBER 10", "NUMBER 3"}},
+		{name: "digit separators", source: "1_000_000", want: []string{"NUMBER 1e+06"}},
+		{name: "method call on a number", source: "1.foo", want: []string{"NUMBER 1", "DOT .", "IDENTIFIER foo"}},
+		{name: "exponent without digits", source: "1e", want: []string{"NUMBER 1", "IDENTIFIER e"}},
+		{name: "trailing separator", source: "1_", want: []string{"NUMBER 1", "IDENTIFIER _"}},
+		{name: "hexadecimal without digits", source: "0x", codes: []lox_error.Code{lox_error.CodeInvalidNumber}},
+		{name: "binary with other digits", source: "0b2", codes: []lox_error.Code{lox_error.CodeInvalidNumber}},
+		{name: "hexadecimal overflow", source: "0x1_0000_0000_0000_0000", codes: []lox_error.Code{lox_error.CodeInvalidNumber}},
+	})
+}
+
+func TestErrorSpans(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string // The code and span of each error, e.g. "E0001 1:2-1:2"
+	}{
+		{name: "unexpected character", source: "x = @;", want: []string{"E0001 1:5-1:5"}},
+		{name: "number out of range", source: "print 1e400;", want: []string{"E0004 1:7-1:11"}},
+		{name: "prefix without digits", source: "\n  0x;", want: []string{"E0004 2:3-2:4"}},
+		{name: "invalid escape", source: `"ab\q"`, want: []string{"E0003 1:4-1:5"}},
+		{name: "invalid unicode escape", source: `"\u{D800}"`, want: []string{"E0003 1:2-1:9"}},
+		{name: "unterminated string", source: "print \"a\nb", want: []string{"E0002 1:7-1:7"}},
+		{name: "unterminated string after an interpolation", source: `"a ${x} b`, want: []string{"E0002 1:1-1:1"}},
+		{name: "unterminated interpolation", source: `x = "a ${ 1 +`, want: []string{"E0002 1:5-1:5"}},
+		{name: "unterminated string in an interpolation", source: `x = "a ${ "b`, want: []string{"E0002 1:11-1:11"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			codeScanner := scanner.NewCodeScanner(1, "test.lox")
+			codeScanner.Run(test.source)
+			got := []string{}
+			for _, err := range codeScanner.Errors() {
+				diagnostic := err.(lox_error.ScannerError).Diagnostic()
+				span := diagnostic.Span
+				got = append(got, fmt.Sprintf("%s %d:%d-%d:%d", diagnostic.Code, span.StartLine, span.StartColumn, span.EndLine, span.EndColumn))
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("errors %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -19,5 +19,11 @@ var Keywords = map[string]TokenType{
 	"true":     TRUE,
 	"var":      VAR,
 	"while":    WHILE,
-	"break":    BREAK, // Missing from the original Lox.
+	"break":    BREAK,    // Missing from the original Lox.
+	"continue": CONTINUE, // Missing from the original Lox.
+	"throw":    THROW,    // Missing from the original Lox.
+	"try":      TRY,      // Missing from the original Lox.
+	"catch":    CATCH,    // Missing from the original Lox.
+	"finally":  FINALLY,  // Missing from the original Lox.
+	"import":   IMPORT,   // Missing from the original Lox.
 }
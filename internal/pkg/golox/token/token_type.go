@@ -4,32 +4,58 @@ type TokenType string
 
 const (
 	// Single-character tokens.
-	LEFT_PAREN  TokenType = "LEFT_PAREN"
-	RIGHT_PAREN TokenType = "RIGHT_PAREN"
-	LEFT_BRACE  TokenType = "LEFT_BRACE"
-	RIGHT_BRACE TokenType = "RIGHT_BRACE"
-	COMMA       TokenType = "COMMA"
-	DOT         TokenType = "DOT"
-	MINUS       TokenType = "MINUS"
-	PLUS        TokenType = "PLUS"
-	SEMICOLON   TokenType = "SEMICOLON"
-	SLASH       TokenType = "SLASH"
-	STAR        TokenType = "STAR"
+	LEFT_PAREN    TokenType = "LEFT_PAREN"
+	RIGHT_PAREN   TokenType = "RIGHT_PAREN"
+	LEFT_BRACE    TokenType = "LEFT_BRACE"
+	RIGHT_BRACE   TokenType = "RIGHT_BRACE"
+	LEFT_BRACKET  TokenType = "LEFT_BRACKET"
+	RIGHT_BRACKET TokenType = "RIGHT_BRACKET"
+	COMMA         TokenType = "COMMA"
+	COLON         TokenType = "COLON"
+	QUESTION      TokenType = "QUESTION"
+	DOT           TokenType = "DOT"
+	MINUS         TokenType = "MINUS"
+	PLUS          TokenType = "PLUS"
+	SEMICOLON     TokenType = "SEMICOLON"
+	SLASH         TokenType = "SLASH"
+	STAR          TokenType = "STAR"
+	PERCENT       TokenType = "PERCENT"
+	AMPERSAND     TokenType = "AMPERSAND"
+	PIPE          TokenType = "PIPE"
+	CARET         TokenType = "CARET"
 
 	// One or two character tokens.
-	BANG          TokenType = "BANG"
-	BANG_EQUAL    TokenType = "BANG_EQUAL"
-	EQUAL         TokenType = "EQUAL"
-	EQUAL_EQUAL   TokenType = "EQUAL_EQUAL"
-	GREATER       TokenType = "GREATER"
-	GREATER_EQUAL TokenType = "GREATER_EQUAL"
-	LESS          TokenType = "LESS"
-	LESS_EQUAL    TokenType = "LESS_EQUAL"
+	BANG              TokenType = "BANG"
+	BANG_EQUAL        TokenType = "BANG_EQUAL"
+	EQUAL             TokenType = "EQUAL"
+	EQUAL_EQUAL       TokenType = "EQUAL_EQUAL"
+	GREATER           TokenType = "GREATER"
+	GREATER_EQUAL     TokenType = "GREATER_EQUAL"
+	LESS              TokenType = "LESS"
+	LESS_EQUAL        TokenType = "LESS_EQUAL"
+	ARROW             TokenType = "ARROW"
+	QUESTION_DOT      TokenType = "QUESTION_DOT"
+	QUESTION_QUESTION TokenType = "QUESTION_QUESTION"
+	STAR_STAR         TokenType = "STAR_STAR"
+	TILDE             TokenType = "TILDE"
+	TILDE_SLASH       TokenType = "TILDE_SLASH"
+	LESS_LESS         TokenType = "LESS_LESS"
+	GREATER_GREATER   TokenType = "GREATER_GREATER"
+	PLUS_PLUS         TokenType = "PLUS_PLUS"
+	MINUS_MINUS       TokenType = "MINUS_MINUS"
+
+	// Compound assignment.
+	PLUS_EQUAL    TokenType = "PLUS_EQUAL"
+	MINUS_EQUAL   TokenType = "MINUS_EQUAL"
+	STAR_EQUAL    TokenType = "STAR_EQUAL"
+	SLASH_EQUAL   TokenType = "SLASH_EQUAL"
+	PERCENT_EQUAL TokenType = "PERCENT_EQUAL"
 
 	// Literals.
-	IDENTIFIER TokenType = "IDENTIFIER"
-	STRING     TokenType = "STRING"
-	NUMBER     TokenType = "NUMBER"
+	IDENTIFIER    TokenType = "IDENTIFIER"
+	STRING        TokenType = "STRING"
+	INTERPOLATION TokenType = "INTERPOLATION" // String segment that ends with "${".
+	NUMBER        TokenType = "NUMBER"
 
 	// Keywords.
 	NIL   TokenType = "NIL"
@@ -44,11 +70,18 @@ const (
 	VAR TokenType = "VAR"
 
 	// Control flow.
-	IF    TokenType = "IF"
-	ELSE  TokenType = "ELSE"
-	FOR   TokenType = "FOR"
-	WHILE TokenType = "WHILE"
-	BREAK TokenType = "BREAK"
+	IF       TokenType = "IF"
+	ELSE     TokenType = "ELSE"
+	FOR      TokenType = "FOR"
+	WHILE    TokenType = "WHILE"
+	BREAK    TokenType = "BREAK"
+	CONTINUE TokenType = "CONTINUE"
+
+	// Exceptions.
+	THROW   TokenType = "THROW"
+	TRY     TokenType = "TRY"
+	CATCH   TokenType = "CATCH"
+	FINALLY TokenType = "FINALLY"
 
 	// Functions and methods.
 	FUN    TokenType = "FUN"
@@ -59,6 +92,9 @@ const (
 	SUPER TokenType = "SUPER"
 	THIS  TokenType = "THIS"
 
+	// Modules. The words "from" and "as" are identifiers, recognized only in import declarations.
+	IMPORT TokenType = "IMPORT"
+
 	// Output.
 	PRINT TokenType = "PRINT"
 
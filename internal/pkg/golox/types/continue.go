@@ -0,0 +1,18 @@
+package types
+
+import "github.com/mejroslav/golox/internal/pkg/golox/token"
+
+// ContinueValue is a special error type used to skip the rest of the current loop iteration.
+type ContinueValue struct {
+	Keyword *token.Token
+}
+
+func NewContinueValue(keyword *token.Token) *ContinueValue {
+	return &ContinueValue{
+		Keyword: keyword,
+	}
+}
+
+func (cv *ContinueValue) Error() string {
+	return "Continue statement encountered"
+}
@@ -0,0 +1,14 @@
+package types
+
+import "github.com/mejroslav/golox/internal/pkg/golox/token"
+
+// ShortCircuitValue is a special error type used by the optional chaining operator "?.".
+// When the object is nil, it skips the rest of the property and call chain, which then
+// evaluates to nil.
+type ShortCircuitValue struct {
+	Operator *token.Token
+}
+
+func (sc *ShortCircuitValue) Error() string {
+	return "Optional chain short-circuited"
+}
@@ -0,0 +1,27 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+	"github.com/mejroslav/golox/internal/pkg/golox/token"
+)
+
+// ThrowValue is a special error type used to propagate a value thrown by a 'throw'
+// statement up the call stack until it is caught by a 'try' statement.
+type ThrowValue struct {
+	Keyword *token.Token
+	Value   any
+	Trace   []lox_error.Frame // The call stack at the 'throw' statement
+}
+
+func NewThrowValue(keyword *token.Token, value any) *ThrowValue {
+	return &ThrowValue{
+		Keyword: keyword,
+		Value:   value,
+	}
+}
+
+func (t *ThrowValue) Error() string {
+	return fmt.Sprintf("Thrown value: %v", t.Value)
+}
@@ -0,0 +1,181 @@
+package lox
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/interpreter"
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+)
+
+// RegisterClass defines a global class bound to a Go struct type. Its instances
+// hold a pointer to a value of the struct:
+//
+//   - exported fields are properties of the instances, which are converted on
+//     every read and write like the arguments and results of RegisterFunc;
+//     the tag `lox:"name"` renames a field and `lox:"-"` hides it,
+//   - exported methods, of the struct or of the pointer to it, are methods of
+//     the class, unless they return more than a value and an error,
+//   - the String method, if there is one, is used when an instance is printed.
+//
+// constructor is either a Go function returning the struct or a pointer to it,
+// optionally with an error, which is called with the arguments of the class, or
+// a value of the struct type or of a pointer to it, in which case the class
+// takes no arguments and new instances hold the zero value.
+//
+// Lox classes can inherit from the class and override its methods. Their
+// initializer can call the constructor with 'super.init(...)'; otherwise their
+// instances hold the zero value. Go values of the struct returned to Lox by
+// functions and fields become instances of the class.
+//
+//	type Point struct{ X, Y float64 }
+//	func (p *Point) Length() float64 { return math.Hypot(p.X, p.Y) }
+//
+//	vm.RegisterClass("Point", func(x, y float64) *Point { return &Point{x, y} })
+func (vm *VM) RegisterClass(name string, constructor any) error {
+	value := reflect.ValueOf(constructor)
+	structType, ok := boundStruct(reflect.TypeOf(constructor))
+	var init *goFunction
+	if value.Kind() == reflect.Func && !value.IsNil() {
+		var err error
+		init, err = vm.newGoFunction(name, value.Type())
+		if err != nil {
+			return fmt.Errorf("cannot register '%s': %w", name, err)
+		}
+		results := value.Type().NumOut()
+		if init.returnsError {
+			results--
+		}
+		if results == 0 {
+			return fmt.Errorf("cannot register '%s': constructor %s must return a struct or a pointer to a struct", name, value.Type())
+		}
+		structType, ok = boundStruct(value.Type().Out(0))
+	}
+	if !ok {
+		return fmt.Errorf("cannot register '%s': %T is neither a struct nor a constructor of a struct", name, constructor)
+	}
+
+	native := &interpreter.NativeClass{
+		New: func() any {
+			return reflect.New(structType).Interface()
+		},
+		Fields:  vm.bindFields(structType),
+		Methods: vm.bindMethods(structType),
+	}
+	if init != nil {
+		native.Methods["init"] = interpreter.NewNativeMethod("init", init.arity(), init.t.IsVariadic(), func(interp *interpreter.Interpreter, this *interpreter.LoxInstance, arguments []any) (any, error) {
+			position := interp.CallPosition()
+			result, err := init.call(position, value, arguments)
+			if err != nil {
+				return nil, err
+			}
+			if result.Kind() != reflect.Pointer {
+				pointer := reflect.New(structType)
+				pointer.Elem().Set(result)
+				result = pointer
+			} else if result.IsNil() {
+				return nil, lox_error.NewRuntimeError(position, lox_error.CodeNativeError, fmt.Sprintf("Constructor of '%s' returned nil.", name))
+			}
+			this.Native = result.Interface()
+			return nil, nil
+		})
+	}
+
+	class := interpreter.NewLoxClass(name, nil, map[string]*interpreter.LoxFunction{})
+	class.Native = native
+	vm.classes[structType] = class
+	vm.interpreter.Globals().Define(name, class)
+	return nil
+}
+
+// boundStruct returns the struct type of a struct or a pointer to a struct.
+func boundStruct(t reflect.Type) (reflect.Type, bool) {
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t, t != nil && t.Kind() == reflect.Struct
+}
+
+// bindFields returns the exported fields of a struct type, including the fields promoted from embedded structs.
+func (vm *VM) bindFields(structType reflect.Type) map[string]*interpreter.NativeField {
+	fields := make(map[string]*interpreter.NativeField)
+	for _, field := range reflect.VisibleFields(structType) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		name := field.Name
+		if tag := field.Tag.Get("lox"); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+
+		fields[name] = &interpreter.NativeField{
+			Get: func(value any) (any, error) {
+				fieldValue, err := reflect.ValueOf(value).Elem().FieldByIndexErr(field.Index)
+				if err != nil {
+					return nil, err
+				}
+				return vm.toLox(fieldValue.Interface())
+			},
+			Set: func(value any, loxValue any) error {
+				fieldValue, err := reflect.ValueOf(value).Elem().FieldByIndexErr(field.Index)
+				if err != nil {
+					return err
+				}
+				converted, err := vm.toGo(loxValue, field.Type)
+				if err != nil {
+					return err
+				}
+				fieldValue.Set(converted)
+				return nil
+			},
+		}
+	}
+	return fields
+}
+
+// bindMethods returns the exported methods of a pointer to a struct type.
+func (vm *VM) bindMethods(structType reflect.Type) map[string]*interpreter.NativeMethod {
+	methods := make(map[string]*interpreter.NativeMethod)
+	pointerType := reflect.PointerTo(structType)
+	for index := 0; index < pointerType.NumMethod(); index++ {
+		name := pointerType.Method(index).Name
+		method, err := vm.newGoFunction(name, reflect.New(structType).Method(index).Type())
+		if err != nil {
+			continue
+		}
+
+		methods[name] = interpreter.NewNativeMethod(name, method.arity(), method.t.IsVariadic(), func(interp *interpreter.Interpreter, this *interpreter.LoxInstance, arguments []any) (any, error) {
+			position := interp.CallPosition()
+			result, err := method.call(position, reflect.ValueOf(this.Native).Method(index), arguments)
+			if err != nil {
+				return nil, err
+			}
+			return method.result(position, result)
+		})
+	}
+	return methods
+}
+
+// newInstance returns an instance of a bound class for a Go value of its
+// struct type, or of a pointer to it, which the instance shares.
+func (vm *VM) newInstance(value reflect.Value) (*interpreter.LoxInstance, bool) {
+	structType, ok := boundStruct(value.Type())
+	if !ok {
+		return nil, false
+	}
+	class, ok := vm.classes[structType]
+	if !ok || (value.Kind() == reflect.Pointer && value.IsNil()) {
+		return nil, false
+	}
+
+	if value.Kind() != reflect.Pointer {
+		pointer := reflect.New(structType)
+		pointer.Elem().Set(value)
+		value = pointer
+	}
+	instance := interpreter.NewLoxInstance(class)
+	instance.Native = value.Interface()
+	return instance, true
+}
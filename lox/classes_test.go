@@ -0,0 +1,236 @@
+package lox_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mejroslav/golox/lox"
+)
+
+type Counter struct {
+	Count  int
+	Step   int    `lox:"step"`
+	Hidden string `lox:"-"`
+	secret int
+}
+
+func (c *Counter) Increment() int { c.Count += c.Step; return c.Count }
+func (c *Counter) Add(n ...int) {
+	for _, m := range n {
+		c.Count += m
+	}
+}
+func (c *Counter) Fail() error     { return errors.New("counter failed") }
+func (c Counter) String() string   { return fmt.Sprintf("Counter(%d)", c.Count) }
+func (c Counter) Pair() (int, int) { return c.Count, c.Step } // Not a method of the class
+
+type Point struct{ X, Y float64 }
+
+type Segment struct {
+	Start, End Point
+	Labels     []string
+}
+
+func TestRegisterClass(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+		code   lox.Code
+	}{
+		{
+			name:   "fields",
+			source: `var c = Counter(2); print c.step; print c.Count; c.Count = 5; print c.Count;`,
+			want:   "2\n0\n5\n",
+		},
+		{
+			name:   "methods",
+			source: `var c = Counter(2); c.Increment(); print c.Increment(); c.Add(1, 2); print c.Count;`,
+			want:   "4\n7\n",
+		},
+		{
+			name:   "bound method",
+			source: `var increment = Counter(3).Increment; increment(); print increment();`,
+			want:   "6\n",
+		},
+		{
+			name:   "String",
+			source: `var c = Counter(1); c.Increment(); print c; print "${c}"; print Counter;`,
+			want:   "Counter(1)\nCounter(1)\n<class Counter>\n",
+		},
+		{
+			name:   "Lox properties",
+			source: `var c = Counter(1); c.extra = "x"; print c.extra;`,
+			want:   "x\n",
+		},
+		{
+			name:   "hidden field",
+			source: `print Counter(1).Hidden;`,
+			code:   "E0102",
+		},
+		{
+			name:   "unexported field",
+			source: `print Counter(1).secret;`,
+			code:   "E0102",
+		},
+		{
+			name:   "method with two results",
+			source: `Counter(1).Pair();`,
+			code:   "E0102",
+		},
+		{
+			name:   "field of the wrong type",
+			source: `Counter(1).Count = "x";`,
+			code:   "E0411",
+		},
+		{
+			name:   "constructor arguments",
+			source: `Counter();`,
+			code:   "E0403",
+		},
+		{
+			name:   "method error",
+			source: `try { Counter(1).Fail(); } catch (e) { print e.message; }`,
+			want:   "counter failed\n",
+		},
+		{
+			name: "subclass",
+			source: `class Fast < Counter {
+				init() { super.init(10); }
+				Increment() { super.Increment(); return super.Increment(); }
+			}
+			var f = Fast(); print f.Increment(); print f; print f.step;`,
+			want: "20\nCounter(20)\n10\n",
+		},
+		{
+			name:   "subclass without an initializer",
+			source: `class Zero < Counter {} var z = Zero(); print z.step; print z;`,
+			want:   "0\nCounter(0)\n",
+		},
+		{
+			name:   "value constructor",
+			source: `var p = Point(); p.X = 3; print p.X + p.Y;`,
+			want:   "3\n",
+		},
+		{
+			name:   "value constructor arguments",
+			source: `Point(1, 2);`,
+			code:   "E0403",
+		},
+		{
+			name:   "struct fields",
+			source: `var s = segment(); print s.End.X; s.Start = s.End; print s.Start.Y; print s.Labels;`,
+			want:   "1\n2\n[\"a\"]\n",
+		},
+		{
+			name:   "instances passed to Go",
+			source: `var c = Counter(2); c.Increment(); print total(c, Counter(5));`,
+			want:   "2\n",
+		},
+		{
+			name:   "other values passed to Go",
+			source: `total(Point(), Counter(1));`,
+			code:   "E0411",
+		},
+		{
+			name:   "constructor error",
+			source: `try { Checked(-1); } catch (e) { print e.message; } print Checked(1).step;`,
+			want:   "negative step\n1\n",
+		},
+		{
+			name:   "constructor returning nil",
+			source: `Checked(0);`,
+			code:   "E0412",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vm, stdout := newVM()
+			for name, constructor := range map[string]any{
+				"Counter": func(step int) *Counter { return &Counter{Step: step} },
+				"Point":   Point{},
+				"Segment": (*Segment)(nil),
+				"Checked": func(step int) (*Counter, error) {
+					if step < 0 {
+						return nil, errors.New("negative step")
+					}
+					if step == 0 {
+						return nil, nil
+					}
+					return &Counter{Step: step}, nil
+				},
+			} {
+				if err := vm.RegisterClass(name, constructor); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for name, fn := range map[string]any{
+				"segment": func() Segment { return Segment{End: Point{1, 2}, Labels: []string{"a"}} },
+				"total":   func(a Counter, b *Counter) int { return a.Count + b.Count },
+			} {
+				if err := vm.RegisterFunc(name, fn); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_, err := vm.Eval(test.source, "test.lox")
+			if stdout.String() != test.want {
+				t.Errorf("output %q, want %q", stdout.String(), test.want)
+			}
+			var code lox.Code
+			if diagnostics := diagnosticsOf(err); len(diagnostics) > 0 {
+				code = diagnostics[0].Code
+			} else if err != nil {
+				t.Fatalf("error %v without a diagnostic", err)
+			}
+			if code != test.code {
+				t.Errorf("error %v, want code %q", err, test.code)
+			}
+		})
+	}
+}
+
+func TestRegisterClassErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		constructor any
+	}{
+		{name: "nil", constructor: nil},
+		{name: "not a struct", constructor: 1},
+		{name: "constructor without result", constructor: func() {}},
+		{name: "constructor of another type", constructor: func() int { return 0 }},
+		{name: "constructor with only an error", constructor: func() error { return nil }},
+		{name: "constructor with two values", constructor: func() (*Point, *Point) { return nil, nil }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vm, _ := newVM()
+			if err := vm.RegisterClass("C", test.constructor); err == nil {
+				t.Error("RegisterClass succeeded")
+			}
+			if _, ok := vm.GetGlobal("C"); ok {
+				t.Error("'C' is defined")
+			}
+		})
+	}
+}
+
+func TestRegisterClassSharesValues(t *testing.T) {
+	vm, _ := newVM()
+	if err := vm.RegisterClass("Counter", (*Counter)(nil)); err != nil {
+		t.Fatal(err)
+	}
+	counter := &Counter{Step: 1}
+	if err := vm.SetGlobal("counter", counter); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vm.Eval(`counter.Increment(); counter.step = 5; counter.Increment();`, "test.lox"); err != nil {
+		t.Fatal(err)
+	}
+	if counter.Count != 6 || counter.Step != 5 {
+		t.Errorf("counter %+v, want Count 6 and Step 5", *counter)
+	}
+}
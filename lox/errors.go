@@ -0,0 +1,101 @@
+package lox
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+)
+
+// Diagnostic describes an error with its position in the source code, in the
+// form shared by all phases of the interpreter.
+type Diagnostic = lox_error.Diagnostic
+
+// Span is a range of source code. Lines and columns start at 1 and the end column is inclusive.
+type Span = lox_error.Span
+
+// Label marks a secondary span related to a diagnostic.
+type Label = lox_error.Label
+
+// Frame is an entry of the call stack of a runtime error.
+type Frame = lox_error.Frame
+
+// Code is a stable identifier of a kind of error, such as "E0101" for an undefined variable.
+type Code = lox_error.Code
+
+// errorList holds the errors found by a phase that reports all errors at once.
+type errorList []error
+
+func (l errorList) Error() string {
+	messages := make([]string, len(l))
+	for i, err := range l {
+		messages[i] = strings.TrimSpace(err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Diagnostics describes the errors, in the order they were found.
+func (l errorList) Diagnostics() []Diagnostic {
+	diagnostics := make([]Diagnostic, 0, len(l))
+	for _, err := range l {
+		var diagnosable lox_error.Diagnosable
+		if errors.As(err, &diagnosable) {
+			diagnostics = append(diagnostics, diagnosable.Diagnostic())
+		}
+	}
+	return diagnostics
+}
+
+// ScanError is returned when the source contains invalid tokens, such as unterminated strings.
+type ScanError struct {
+	errorList
+}
+
+// ParseError is returned when the source is not syntactically valid.
+type ParseError struct {
+	errorList
+}
+
+// ResolveError is returned when the static analysis of the source fails, for
+// example because of a 'return' outside of a function.
+type ResolveError struct {
+	errorList
+}
+
+// RuntimeError is returned when the execution fails: because of an error in the
+// program, an uncaught exception, an exceeded execution limit or a bug of the
+// interpreter. It wraps the error of the context when the context is done.
+type RuntimeError struct {
+	err error
+}
+
+func newRuntimeError(err error) error {
+	if _, ok := err.(lox_error.Diagnosable); !ok {
+		return err
+	}
+	return &RuntimeError{err: err}
+}
+
+func (e *RuntimeError) Error() string {
+	return strings.TrimSpace(e.err.Error())
+}
+
+// Unwrap returns the cause of the error, e.g. context.DeadlineExceeded.
+func (e *RuntimeError) Unwrap() error {
+	return errors.Unwrap(e.err)
+}
+
+// Diagnostic describes the error, including the call stack.
+func (e *RuntimeError) Diagnostic() Diagnostic {
+	return e.err.(lox_error.Diagnosable).Diagnostic()
+}
+
+// Limit returns the execution limit that was exceeded, "time", "steps",
+// "allocations" or "canceled", or an empty string for other errors.
+func (e *RuntimeError) Limit() string {
+	var limitErr lox_error.LimitError
+	if errors.As(e.err, &limitErr) {
+		return string(limitErr.Limit)
+	}
+	return ""
+}
@@ -0,0 +1,257 @@
+// Package lox embeds the GoLox interpreter in Go programs.
+//
+// A VM keeps the global variables between evaluations, so a program can be
+// loaded once and its functions called from Go afterwards:
+//
+//	vm := lox.NewVM()
+//	vm.SetStdout(&output)
+//	if _, err := vm.Eval(`fun greet(name) { return "Hello, " + name + "!"; }`, "greet.lox"); err != nil {
+//		return err
+//	}
+//	greeting, err := vm.Call("greet", "Lox") // "Hello, Lox!"
+//
+// Lox values are represented in Go as nil, bool, float64 and string. Other
+// values, such as functions, classes, instances, lists and maps, are opaque:
+// they can be stored and passed back to the VM, and formatted with fmt.
+//
+// Errors are of the types ScanError, ParseError, ResolveError and RuntimeError,
+// depending on the phase of the interpreter that failed.
+package lox
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/ast"
+	"github.com/mejroslav/golox/internal/pkg/golox/interpreter"
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+	"github.com/mejroslav/golox/internal/pkg/golox/parser"
+	"github.com/mejroslav/golox/internal/pkg/golox/resolver"
+	"github.com/mejroslav/golox/internal/pkg/golox/scanner"
+)
+
+// Value is a Lox value, see the package documentation.
+type Value = any
+
+// Limits are the execution budgets of the VM. A zero value disables a limit.
+type Limits = interpreter.Limits
+
+// DefaultMaxCallDepth is the default limit of nested calls, see VM.SetMaxCallDepth.
+const DefaultMaxCallDepth = interpreter.DefaultMaxCallDepth
+
+// VM is a Lox virtual machine: an interpreter with its own global variables,
+// input and output. A VM must not be used by several goroutines at once.
+type VM struct {
+	interpreter *interpreter.Interpreter
+	stderr      io.Writer
+	warnings    bool                                   // Whether warnings are written to stderr
+	sources     map[string]string                      // The evaluated sources by file name, to render warnings
+	classes     map[reflect.Type]*interpreter.LoxClass // The classes bound to Go struct types
+}
+
+// NewVM returns a VM with the built-in functions defined, reading from os.Stdin
+// and writing to os.Stdout and os.Stderr.
+func NewVM() *VM {
+	vm := &VM{
+		interpreter: interpreter.NewInterpreter(),
+		stderr:      os.Stderr,
+		sources:     make(map[string]string),
+		classes:     make(map[reflect.Type]*interpreter.LoxClass),
+	}
+	vm.interpreter.SetModuleLoader(vm.loadModule)
+	return vm
+}
+
+// SetStdin sets the reader from which 'input()' reads lines.
+func (vm *VM) SetStdin(r io.Reader) {
+	vm.interpreter.SetStdin(r)
+}
+
+// SetStdout sets the writer to which 'print' writes.
+func (vm *VM) SetStdout(w io.Writer) {
+	vm.interpreter.SetStdout(w)
+}
+
+// SetStderr sets the writer to which warnings are written, see SetWarnings.
+func (vm *VM) SetStderr(w io.Writer) {
+	vm.stderr = w
+}
+
+// SetWarnings enables writing the warnings of the static analysis, such as
+// unused variables, to stderr before code runs.
+func (vm *VM) SetWarnings(enabled bool) {
+	vm.warnings = enabled
+}
+
+// SetLimits sets the execution budgets of every call of Eval, RunFile and Call.
+func (vm *VM) SetLimits(limits Limits) {
+	vm.interpreter.SetLimits(limits)
+}
+
+// SetMaxCallDepth sets the limit of nested calls before a "Stack overflow" runtime error, 0 for no limit.
+func (vm *VM) SetMaxCallDepth(depth int) {
+	vm.interpreter.SetMaxCallDepth(depth)
+}
+
+// Eval runs Lox source code. The file name is used in error messages and to
+// resolve the paths of imported modules. It returns the value of the last
+// statement if it is an expression statement, nil otherwise.
+func (vm *VM) Eval(source string, filename string) (Value, error) {
+	return vm.EvalContext(context.Background(), source, filename)
+}
+
+// EvalContext is like Eval, but stops with a RuntimeError when ctx is done.
+func (vm *VM) EvalContext(ctx context.Context, source string, filename string) (Value, error) {
+	statements, err := vm.compile(source, filename)
+	if err != nil {
+		return nil, err
+	}
+	return vm.run(func() (any, error) {
+		return vm.interpreter.InterpretContext(ctx, statements)
+	})
+}
+
+// RunFile runs the Lox file at path.
+func (vm *VM) RunFile(path string) error {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	statements, err := vm.compile(string(source), path)
+	if err != nil {
+		return err
+	}
+	exit, err := vm.interpreter.EnterMainFile(path, statements)
+	if err != nil {
+		return err
+	}
+	defer exit()
+	_, err = vm.run(func() (any, error) {
+		return vm.interpreter.Interpret(statements)
+	})
+	return err
+}
+
+// GetGlobal returns the value of a global variable, or false if it is not defined.
+func (vm *VM) GetGlobal(name string) (Value, bool) {
+	value, err := vm.interpreter.Globals().GetAt(0, name)
+	return value, err == nil
+}
+
+// SetGlobal defines a global variable, or replaces its value. Go integers and
+// floats are converted to numbers, slices and arrays to lists, and maps with
+// string keys to maps; other values must be Lox values.
+func (vm *VM) SetGlobal(name string, value any) error {
+	loxValue, err := vm.toLox(value)
+	if err != nil {
+		return fmt.Errorf("cannot set global '%s': %w", name, err)
+	}
+	vm.interpreter.Globals().Define(name, loxValue)
+	return nil
+}
+
+// Call calls the global function or class with the given name. The arguments
+// are converted like the values of SetGlobal.
+func (vm *VM) Call(name string, arguments ...any) (Value, error) {
+	return vm.CallContext(context.Background(), name, arguments...)
+}
+
+// CallContext is like Call, but stops with a RuntimeError when ctx is done.
+func (vm *VM) CallContext(ctx context.Context, name string, arguments ...any) (Value, error) {
+	callee, ok := vm.GetGlobal(name)
+	if !ok {
+		return nil, fmt.Errorf("undefined function '%s'", name)
+	}
+
+	loxArguments := make([]any, len(arguments))
+	for i, argument := range arguments {
+		value, err := vm.toLox(argument)
+		if err != nil {
+			return nil, fmt.Errorf("cannot call '%s': argument %d: %w", name, i+1, err)
+		}
+		loxArguments[i] = value
+	}
+
+	return vm.run(func() (any, error) {
+		value, err := vm.interpreter.CallContext(ctx, callee, loxArguments)
+		if err != nil {
+			if _, ok := err.(lox_error.Diagnosable); !ok {
+				return nil, fmt.Errorf("cannot call '%s': %w", name, err)
+			}
+		}
+		return value, err
+	})
+}
+
+// compile scans, parses and resolves source.
+func (vm *VM) compile(source string, filename string) ([]ast.Stmt, error) {
+	vm.sources[filename] = source
+
+	codeScanner := scanner.NewCodeScanner(1, filename)
+	tokens, scanErr := codeScanner.Run(source)
+	if scanErr {
+		return nil, &ScanError{errorList(codeScanner.Errors())}
+	}
+
+	parser := parser.NewParser(tokens)
+	statements, parseErr := parser.Parse()
+	if parseErr {
+		return nil, &ParseError{errorList(parser.Errors())}
+	}
+
+	resolver := resolver.NewResolver(vm.interpreter)
+	resolver.SetSuppressions(codeScanner.Suppressions())
+	statements, resolveErr := resolver.Resolve(statements)
+	if resolveErr {
+		return nil, &ResolveError{errorList(resolver.Errors())}
+	}
+	if vm.warnings {
+		renderer := lox_error.Renderer{Source: vm.sourceLine}
+		for _, warning := range resolver.Warnings() {
+			fmt.Fprintln(vm.stderr, renderer.Render(warning.(lox_error.Diagnosable).Diagnostic()))
+		}
+	}
+	return statements, nil
+}
+
+// loadModule reads and compiles an imported module.
+func (vm *VM) loadModule(path string) ([]ast.Stmt, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return vm.compile(string(source), path)
+}
+
+// run calls f and converts its errors, including Go panics, into RuntimeErrors.
+func (vm *VM) run(f func() (any, error)) (value Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			position, _ := vm.interpreter.Position()
+			vm.interpreter.Unwind()
+			err = newRuntimeError(lox_error.InternalError{Token: position, Message: fmt.Sprint(r)})
+		}
+	}()
+
+	value, err = f()
+	if err != nil {
+		return nil, newRuntimeError(err)
+	}
+	return value, nil
+}
+
+func (vm *VM) sourceLine(file string, line int) (string, bool) {
+	source, ok := vm.sources[file]
+	if !ok {
+		return "", false
+	}
+	lines := strings.Split(source, "\n")
+	if line < 1 || line > len(lines) {
+		return "", false
+	}
+	return lines[line-1], true
+}
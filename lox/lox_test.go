@@ -0,0 +1,356 @@
+package lox_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mejroslav/golox/lox"
+)
+
+// newVM returns a VM that writes to the returned builder.
+func newVM() (*lox.VM, *strings.Builder) {
+	vm := lox.NewVM()
+	var stdout strings.Builder
+	vm.SetStdout(&stdout)
+	return vm, &stdout
+}
+
+// diagnosticsOf returns the diagnostics of an error returned by the VM.
+func diagnosticsOf(err error) []lox.Diagnostic {
+	var list interface{ Diagnostics() []lox.Diagnostic }
+	if errors.As(err, &list) {
+		return list.Diagnostics()
+	}
+	var runtimeErr *lox.RuntimeError
+	if errors.As(err, &runtimeErr) {
+		return []lox.Diagnostic{runtimeErr.Diagnostic()}
+	}
+	return nil
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   lox.Value
+		output string
+	}{
+		{name: "number", source: `1 + 2;`, want: 3.0},
+		{name: "string", source: `"a" + "b";`, want: "ab"},
+		{name: "boolean", source: `1 < 2;`, want: true},
+		{name: "nil", source: `nil;`, want: nil},
+		{name: "last statement is not an expression", source: `1; var x = 2;`, want: nil},
+		{name: "output", source: `print "hello"; 1;`, want: 1.0, output: "hello\n"},
+		{name: "empty", source: ``, want: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vm, stdout := newVM()
+			got, err := vm.Eval(test.source, "test.lox")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("got %#v, want %#v", got, test.want)
+			}
+			if stdout.String() != test.output {
+				t.Errorf("output %q, want %q", stdout.String(), test.output)
+			}
+		})
+	}
+}
+
+func TestEvalKeepsGlobals(t *testing.T) {
+	vm, _ := newVM()
+	if _, err := vm.Eval(`var count = 1; fun increment() { count = count + 1; return count; }`, "a.lox"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := vm.Eval(`increment(); increment();`, "b.lox"); err != nil || got != 3.0 {
+		t.Errorf("got %v, %v, want 3", got, err)
+	}
+	if got, ok := vm.GetGlobal("count"); !ok || got != 3.0 {
+		t.Errorf("count = %v, %v, want 3", got, ok)
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		target any // A pointer to the expected type of the error
+		codes  []lox.Code
+	}{
+		{
+			name:   "scan",
+			source: "var s = \"unterminated;",
+			target: new(*lox.ScanError),
+			codes:  []lox.Code{"E0002"},
+		},
+		{
+			name:   "parse",
+			source: "var = 1;\nprint ;",
+			target: new(*lox.ParseError),
+			codes:  []lox.Code{"E0201", "E0202"},
+		},
+		{
+			name:   "resolve",
+			source: "return 1;\nbreak;",
+			target: new(*lox.ResolveError),
+			codes:  []lox.Code{"E0301", "E0303"},
+		},
+		{
+			name:   "runtime",
+			source: `print undefined;`,
+			target: new(*lox.RuntimeError),
+			codes:  []lox.Code{"E0101"},
+		},
+		{
+			name:   "uncaught exception",
+			source: `throw "oops";`,
+			target: new(*lox.RuntimeError),
+			codes:  []lox.Code{"E0408"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vm, _ := newVM()
+			_, err := vm.Eval(test.source, "test.lox")
+			if !errors.As(err, test.target) {
+				t.Fatalf("error %v of type %T, want %T", err, err, test.target)
+			}
+			diagnostics := diagnosticsOf(err)
+			codes := []lox.Code{}
+			for _, diagnostic := range diagnostics {
+				codes = append(codes, diagnostic.Code)
+				if diagnostic.Span.File != "test.lox" || diagnostic.Span.StartLine == 0 {
+					t.Errorf("diagnostic %q at %+v, want a position in test.lox", diagnostic.Message, diagnostic.Span)
+				}
+			}
+			if !slices.Equal(codes, test.codes) {
+				t.Errorf("codes %v, want %v", codes, test.codes)
+			}
+		})
+	}
+}
+
+func TestGlobals(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string // The value printed by Lox
+	}{
+		{name: "integer", value: 42, want: "42"},
+		{name: "unsigned", value: uint8(7), want: "7"},
+		{name: "float", value: float32(1.5), want: "1.5"},
+		{name: "string", value: "text", want: "text"},
+		{name: "boolean", value: true, want: "true"},
+		{name: "nil", value: nil, want: "nil"},
+		{name: "slice", value: []int{1, 2}, want: "[1, 2]"},
+		{name: "array", value: [2]string{"a", "b"}, want: `["a", "b"]`},
+		{name: "nested", value: [][]bool{{true}, {}}, want: "[[true], []]"},
+		{name: "map", value: map[string]int{"a": 1}, want: `{"a": 1}`},
+		{name: "nil slice", value: []int(nil), want: "nil"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vm, stdout := newVM()
+			if err := vm.SetGlobal("value", test.value); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := vm.Eval(`print value;`, "test.lox"); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSuffix(stdout.String(), "\n"); got != test.want {
+				t.Errorf("printed %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGlobalErrors(t *testing.T) {
+	vm, _ := newVM()
+	if err := vm.SetGlobal("channel", make(chan int)); err == nil {
+		t.Error("SetGlobal of a channel succeeded")
+	}
+	if err := vm.SetGlobal("keys", map[int]int{1: 1}); err == nil {
+		t.Error("SetGlobal of a map with integer keys succeeded")
+	}
+	if _, ok := vm.GetGlobal("missing"); ok {
+		t.Error("GetGlobal of an undefined variable succeeded")
+	}
+}
+
+func TestCall(t *testing.T) {
+	vm, stdout := newVM()
+	_, err := vm.Eval(`
+fun add(a, b) { return a + b; }
+fun fail() {
+  return nil.field;
+}
+class Point {
+  init(x, y) { this.x = x; this.y = y; }
+}
+var notFunction = 1;`, "test.lox")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := vm.Call("add", 1, 2.5); err != nil || got != 3.5 {
+		t.Errorf("add(1, 2.5) = %v, %v, want 3.5", got, err)
+	}
+	if got, err := vm.Call("add", "a", "b"); err != nil || got != "ab" {
+		t.Errorf(`add("a", "b") = %v, %v, want "ab"`, got, err)
+	}
+
+	point, err := vm.Call("Point", 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.SetGlobal("p", point); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vm.Eval(`print p.x + p.y;`, "test.lox"); err != nil || stdout.String() != "3\n" {
+		t.Errorf("printed %q, %v, want 3", stdout.String(), err)
+	}
+
+	for _, call := range []struct {
+		name      string
+		arguments []any
+	}{
+		{"missing", nil},
+		{"notFunction", nil},
+		{"add", []any{1}},
+		{"add", []any{make(chan int), 1}},
+	} {
+		if _, err := vm.Call(call.name, call.arguments...); err == nil || errors.As(err, new(*lox.RuntimeError)) {
+			t.Errorf("%s%v: error %v, want a Go error", call.name, call.arguments, err)
+		}
+	}
+
+	_, err = vm.Call("fail")
+	var runtimeErr *lox.RuntimeError
+	if !errors.As(err, &runtimeErr) {
+		t.Fatalf("fail(): error %v, want a RuntimeError", err)
+	}
+	diagnostic := runtimeErr.Diagnostic()
+	if diagnostic.Span.StartLine != 4 || len(diagnostic.Trace) != 1 || diagnostic.Trace[0].Function != "fail" {
+		t.Errorf("error at line %d with trace %v, want line 4 in 'fail'", diagnostic.Span.StartLine, diagnostic.Trace)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name   string
+		limits lox.Limits
+		ctx    context.Context
+		limit  string
+		cause  error
+	}{
+		{name: "steps", limits: lox.Limits{MaxSteps: 1000}, ctx: context.Background(), limit: "steps"},
+		{name: "canceled", ctx: canceled, limit: "canceled", cause: context.Canceled},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vm, stdout := newVM()
+			vm.SetLimits(test.limits)
+			_, err := vm.EvalContext(test.ctx, "var i = 0;\nwhile (true) i = i + 1;", "test.lox")
+			var runtimeErr *lox.RuntimeError
+			if !errors.As(err, &runtimeErr) || runtimeErr.Limit() != test.limit {
+				t.Fatalf("error %v, want the limit %q", err, test.limit)
+			}
+			if code := runtimeErr.Diagnostic().Code; code != "E0410" {
+				t.Errorf("code %s, want E0410", code)
+			}
+			if test.cause != nil && !errors.Is(err, test.cause) {
+				t.Errorf("error %v does not wrap %v", err, test.cause)
+			}
+
+			// The VM can be used again afterwards.
+			vm.SetLimits(lox.Limits{})
+			if _, err := vm.Eval(`print "again";`, "test.lox"); err != nil || stdout.String() != "again\n" {
+				t.Errorf("printed %q, %v, want again", stdout.String(), err)
+			}
+		})
+	}
+}
+
+func TestMaxCallDepth(t *testing.T) {
+	vm, _ := newVM()
+	vm.SetMaxCallDepth(20)
+	if _, err := vm.Eval(`fun f(n) { if (n > 0) f(n - 1); } f(19);`, "test.lox"); err != nil {
+		t.Fatal(err)
+	}
+	_, err := vm.Eval(`f(20);`, "test.lox")
+	if diagnostics := diagnosticsOf(err); len(diagnostics) != 1 || diagnostics[0].Code != "E0409" {
+		t.Errorf("error %v, want a stack overflow", err)
+	}
+}
+
+func TestWarnings(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		vm, _ := newVM()
+		var stderr strings.Builder
+		vm.SetStderr(&stderr)
+		vm.SetWarnings(enabled)
+		if _, err := vm.Eval("fun f() {\n  var unused = 1;\n}", "test.lox"); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Contains(stderr.String(), "W0001"); got != enabled {
+			t.Errorf("warnings enabled %v, but stderr %q", enabled, stderr.String())
+		}
+	}
+}
+
+func TestRunFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.lox":     `import "lib/util.lox" as util; print util.double(21);`,
+		"lib/util.lox": `fun double(x) { return x * 2; }`,
+		"cycle.lox":    `import "cycle.lox" as self;`,
+		"entry.lox":    `print "entry runs"; import "back.lox" as back;`,
+		"back.lox":     `import "entry.lox" as entry;`,
+	}
+	for name, source := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	vm, stdout := newVM()
+	if err := vm.RunFile(filepath.Join(dir, "main.lox")); err != nil || stdout.String() != "42\n" {
+		t.Errorf("printed %q, %v, want 42", stdout.String(), err)
+	}
+	err := vm.RunFile(filepath.Join(dir, "cycle.lox"))
+	if diagnostics := diagnosticsOf(err); len(diagnostics) != 1 || diagnostics[0].Code != "E0502" {
+		t.Errorf("error %v, want an import cycle", err)
+	}
+
+	// The main file does not run again when a module imports it.
+	stdout.Reset()
+	err = vm.RunFile(filepath.Join(dir, "entry.lox"))
+	if diagnostics := diagnosticsOf(err); len(diagnostics) != 1 || diagnostics[0].Message != "Import cycle detected: entry.lox -> back.lox -> entry.lox." {
+		t.Errorf("error %v, want an import cycle through entry.lox", err)
+	}
+	if stdout.String() != "entry runs\n" {
+		t.Errorf("printed %q, want entry.lox to run once", stdout.String())
+	}
+	if err := vm.RunFile(filepath.Join(dir, "missing.lox")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("error %v, want a missing file", err)
+	}
+}
@@ -0,0 +1,138 @@
+package lox
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/interpreter"
+	"github.com/mejroslav/golox/internal/pkg/golox/lox_error"
+	"github.com/mejroslav/golox/internal/pkg/golox/token"
+)
+
+// RegisterFunc defines a global native function implemented by the Go function fn.
+//
+// The arguments are converted from Lox values to the types of the parameters:
+// numbers to any integer or float type (integers only if they have no fractional
+// part and fit), strings, booleans, lists to slices, instances of classes bound
+// with RegisterClass to their Go values, and nil to pointers, slices and maps.
+// Parameters of type Value receive the Lox value as it is. A variadic fn accepts
+// any number of arguments beyond its fixed parameters. An argument that cannot
+// be converted raises a runtime error.
+//
+// fn may return nothing, a value, an error, or a value and an error. The value
+// is converted like the values of SetGlobal, and a non-nil error raises a runtime
+// error with its message, which Lox code can catch.
+//
+//	vm.RegisterFunc("repeat", strings.Repeat)
+//	vm.RegisterFunc("readFile", func(path string) (string, error) {
+//		data, err := os.ReadFile(path)
+//		return string(data), err
+//	})
+func (vm *VM) RegisterFunc(name string, fn any) error {
+	function := reflect.ValueOf(fn)
+	if function.Kind() != reflect.Func || function.IsNil() {
+		return fmt.Errorf("cannot register '%s': %T is not a function", name, fn)
+	}
+	goFunc, err := vm.newGoFunction(name, function.Type())
+	if err != nil {
+		return fmt.Errorf("cannot register '%s': %w", name, err)
+	}
+
+	call := func(interp *interpreter.Interpreter, arguments []any) (any, error) {
+		position := interp.CallPosition()
+		result, err := goFunc.call(position, function, arguments)
+		if err != nil {
+			return nil, err
+		}
+		return goFunc.result(position, result)
+	}
+	if goFunc.t.IsVariadic() {
+		vm.interpreter.Globals().Define(name, interpreter.NewVariadicNativeFunction(name, goFunc.arity(), call))
+	} else {
+		vm.interpreter.Globals().Define(name, interpreter.NewNativeFunction(name, goFunc.arity(), call))
+	}
+	return nil
+}
+
+// goFunction calls Go functions of a given type with Lox arguments.
+type goFunction struct {
+	vm           *VM
+	name         string       // The name of the function in Lox, for error messages
+	t            reflect.Type // The type of the function
+	returnsError bool         // Whether the last result is an error
+}
+
+func (vm *VM) newGoFunction(name string, t reflect.Type) (*goFunction, error) {
+	returnsError := t.NumOut() > 0 && t.Out(t.NumOut()-1) == errorType
+	if t.NumOut() > 2 || (t.NumOut() == 2 && !returnsError) {
+		return nil, fmt.Errorf("%s must return at most a value and an error", t)
+	}
+	return &goFunction{vm: vm, name: name, t: t, returnsError: returnsError}, nil
+}
+
+// arity returns the number of fixed parameters.
+func (f *goFunction) arity() int {
+	if f.t.IsVariadic() {
+		return f.t.NumIn() - 1
+	}
+	return f.t.NumIn()
+}
+
+// call converts the arguments, calls the function, and returns its result
+// value, which is invalid if it has none. The position is the call in Lox code.
+func (f *goFunction) call(position token.Token, function reflect.Value, arguments []any) (reflect.Value, error) {
+	in := make([]reflect.Value, len(arguments))
+	for i, argument := range arguments {
+		value, err := f.vm.toGo(argument, f.parameterType(i))
+		if err != nil {
+			return reflect.Value{}, lox_error.NewRuntimeError(position, lox_error.CodeInvalidArgument,
+				fmt.Sprintf("Invalid argument %d of '%s': %s.", i+1, f.name, err))
+		}
+		in[i] = value
+	}
+
+	out := function.Call(in)
+	if f.returnsError {
+		if err, _ := out[len(out)-1].Interface().(error); err != nil {
+			return reflect.Value{}, nativeError(position, err)
+		}
+		out = out[:len(out)-1]
+	}
+	if len(out) == 0 {
+		return reflect.Value{}, nil
+	}
+	return out[0], nil
+}
+
+// result converts the result value of a call to a Lox value.
+func (f *goFunction) result(position token.Token, value reflect.Value) (any, error) {
+	if !value.IsValid() {
+		return nil, nil
+	}
+	result, err := f.vm.toLox(value.Interface())
+	if err != nil {
+		return nil, lox_error.NewRuntimeError(position, lox_error.CodeNativeError,
+			fmt.Sprintf("Invalid result of '%s': %s.", f.name, err))
+	}
+	return result, nil
+}
+
+// parameterType returns the type of the i-th argument, which is the element
+// type of the variadic parameter for the extra arguments.
+func (f *goFunction) parameterType(i int) reflect.Type {
+	if f.t.IsVariadic() && i >= f.t.NumIn()-1 {
+		return f.t.In(f.t.NumIn() - 1).Elem()
+	}
+	return f.t.In(i)
+}
+
+// nativeError converts an error returned by a Go function into a runtime error
+// at the call. Errors of a VM called back by the function keep their position.
+func nativeError(position token.Token, err error) error {
+	var runtimeErr *RuntimeError
+	if errors.As(err, &runtimeErr) {
+		return runtimeErr.err
+	}
+	return lox_error.NewRuntimeError(position, lox_error.CodeNativeError, err.Error())
+}
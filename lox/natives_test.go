@@ -0,0 +1,224 @@
+package lox_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mejroslav/golox/lox"
+)
+
+func TestRegisterFunc(t *testing.T) {
+	tests := []struct {
+		name   string
+		fn     any // Registered as 'f'
+		source string
+		want   string
+		code   lox.Code
+	}{
+		{
+			name:   "strings and integers",
+			fn:     strings.Repeat,
+			source: `print f("ab", 3);`,
+			want:   "ababab\n",
+		},
+		{
+			name:   "integer types",
+			fn:     func(a int8, b uint16, c int64) int { return int(a) + int(b) + int(c) },
+			source: `print f(-1, 2, 3);`,
+			want:   "4\n",
+		},
+		{
+			name:   "floats",
+			fn:     func(a float32, b float64) float64 { return float64(a) * b },
+			source: `print f(0.5, 3);`,
+			want:   "1.5\n",
+		},
+		{
+			name:   "booleans",
+			fn:     func(b bool) bool { return !b },
+			source: `print f(false);`,
+			want:   "true\n",
+		},
+		{
+			name:   "slices",
+			fn:     func(xs []int) []string { return []string{fmt.Sprint(len(xs)), fmt.Sprint(xs[0])} },
+			source: `print f([7, 8]);`,
+			want:   "[\"2\", \"7\"]\n",
+		},
+		{
+			name:   "maps",
+			fn:     func() map[string][]bool { return map[string][]bool{"k": {true}} },
+			source: `print f();`,
+			want:   "{\"k\": [true]}\n",
+		},
+		{
+			name:   "Lox values",
+			fn:     func(v lox.Value) lox.Value { return v },
+			source: `print f([1, {"a": nil}]); print f(f);`,
+			want:   "[1, {\"a\": nil}]\n<native fn f>\n",
+		},
+		{
+			name:   "nil pointers",
+			fn:     func(p *int, xs []int) bool { return p == nil && xs == nil },
+			source: `print f(nil, nil);`,
+			want:   "true\n",
+		},
+		{
+			name:   "no result",
+			fn:     func() {},
+			source: `print f();`,
+			want:   "nil\n",
+		},
+		{
+			name:   "variadic",
+			fn:     func(sep string, parts ...string) string { return strings.Join(parts, sep) },
+			source: `print f("-", "a", "b", "c"); print f("-");`,
+			want:   "a-b-c\n\n",
+		},
+		{
+			name:   "variadic without the fixed arguments",
+			fn:     func(sep string, parts ...string) string { return strings.Join(parts, sep) },
+			source: `f();`,
+			code:   "E0403",
+		},
+		{
+			name:   "too few arguments",
+			fn:     strings.Repeat,
+			source: `f("a");`,
+			code:   "E0403",
+		},
+		{
+			name:   "fractional integer",
+			fn:     func(n int) int { return n },
+			source: `f(2.5);`,
+			code:   "E0411",
+		},
+		{
+			name:   "integer overflow",
+			fn:     func(n int8) int8 { return n },
+			source: `f(300);`,
+			code:   "E0411",
+		},
+		{
+			name:   "negative unsigned integer",
+			fn:     func(n uint) uint { return n },
+			source: `f(-1);`,
+			code:   "E0411",
+		},
+		{
+			name:   "wrong type",
+			fn:     func(s string) string { return s },
+			source: `f(true);`,
+			code:   "E0411",
+		},
+		{
+			name:   "wrong element type",
+			fn:     func(xs []string) int { return len(xs) },
+			source: `f(["a", 1]);`,
+			code:   "E0411",
+		},
+		{
+			name:   "nil for a value",
+			fn:     func(n float64) float64 { return n },
+			source: `f(nil);`,
+			code:   "E0411",
+		},
+		{
+			name:   "error",
+			fn:     func() error { return errors.New("boom") },
+			source: `f();`,
+			code:   "E0412",
+		},
+		{
+			name: "error can be caught",
+			fn: func(fail bool) (string, error) {
+				if fail {
+					return "", errors.New("boom")
+				}
+				return "ok", nil
+			},
+			source: `print f(false); try { f(true); } catch (e) { print e.message; }`,
+			want:   "ok\nboom\n",
+		},
+		{
+			name:   "unsupported result",
+			fn:     func() chan int { return nil },
+			source: `f();`,
+			code:   "E0412",
+		},
+		{
+			name:   "panic",
+			fn:     func() { panic("bad") },
+			source: `f();`,
+			code:   "E0901",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vm, stdout := newVM()
+			if err := vm.RegisterFunc("f", test.fn); err != nil {
+				t.Fatal(err)
+			}
+			_, err := vm.Eval(test.source, "test.lox")
+			if stdout.String() != test.want {
+				t.Errorf("output %q, want %q", stdout.String(), test.want)
+			}
+			var code lox.Code
+			if diagnostics := diagnosticsOf(err); len(diagnostics) > 0 {
+				code = diagnostics[0].Code
+			} else if err != nil {
+				t.Fatalf("error %v without a diagnostic", err)
+			}
+			if code != test.code {
+				t.Errorf("error %v, want code %q", err, test.code)
+			}
+		})
+	}
+}
+
+func TestRegisterFuncErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   any
+	}{
+		{name: "not a function", fn: 1},
+		{name: "nil function", fn: (func())(nil)},
+		{name: "two values", fn: func() (int, int) { return 0, 0 }},
+		{name: "three results", fn: func() (int, int, error) { return 0, 0, nil }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vm, _ := newVM()
+			if err := vm.RegisterFunc("f", test.fn); err == nil {
+				t.Error("RegisterFunc succeeded")
+			}
+			if _, ok := vm.GetGlobal("f"); ok {
+				t.Error("'f' is defined")
+			}
+		})
+	}
+}
+
+func TestRegisterFuncCallback(t *testing.T) {
+	vm, stdout := newVM()
+	err := vm.RegisterFunc("callback", func(name string, argument float64) (lox.Value, error) {
+		return vm.Call(name, argument)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = vm.Eval("fun double(x) { return x * 2; }\nfun fail(x) {\n  return x.field;\n}\nprint callback(\"double\", 21);\ncallback(\"fail\", 1);", "test.lox")
+	if stdout.String() != "42\n" {
+		t.Errorf("output %q, want 42", stdout.String())
+	}
+
+	// The error of the callback keeps its position in the Lox function.
+	diagnostics := diagnosticsOf(err)
+	if len(diagnostics) != 1 || diagnostics[0].Code != "E0404" || diagnostics[0].Span.StartLine != 3 {
+		t.Errorf("error %v, want E0404 at line 3", err)
+	}
+}
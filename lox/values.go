@@ -0,0 +1,158 @@
+package lox
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/mejroslav/golox/internal/pkg/golox/interpreter"
+)
+
+var (
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+	valueType = reflect.TypeOf((*Value)(nil)).Elem()
+)
+
+// toLox converts a Go value to a Lox value. Numbers of all Go types become
+// float64, slices and arrays become lists, maps with string keys become maps,
+// structs of types bound with RegisterClass and pointers to them become
+// instances, and values of the interpreter are passed through.
+func (vm *VM) toLox(value any) (any, error) {
+	switch v := value.(type) {
+	case nil, bool, float64, string:
+		return v, nil
+	case interpreter.LoxCallable, *interpreter.LoxInstance, *interpreter.LoxList, *interpreter.LoxMap,
+		*interpreter.LoxError, *interpreter.LoxModule:
+		return v, nil
+	}
+
+	v := reflect.ValueOf(value)
+	if instance, ok := vm.newInstance(v); ok {
+		return instance, nil
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil, nil
+		}
+		elements := make([]any, v.Len())
+		for i := range elements {
+			element, err := vm.toLox(v.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = element
+		}
+		return interpreter.NewLoxList(elements), nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if v.IsNil() {
+			return nil, nil
+		}
+		loxMap := interpreter.NewLoxMap()
+		iter := v.MapRange()
+		for iter.Next() {
+			element, err := vm.toLox(iter.Value().Interface())
+			if err != nil {
+				return nil, err
+			}
+			loxMap.Put(iter.Key().String(), element)
+		}
+		return loxMap, nil
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported value of type %T", value)
+}
+
+// toGo converts a Lox value to a Go value of the given type. Numbers are
+// converted to integers only if they have no fractional part and fit in the type.
+// Instances of bound classes are converted to their Go values, unless the type
+// is the empty interface.
+func (vm *VM) toGo(value any, t reflect.Type) (reflect.Value, error) {
+	if value == nil {
+		switch t.Kind() {
+		case reflect.Interface, reflect.Pointer, reflect.Slice, reflect.Map:
+			return reflect.Zero(t), nil
+		}
+		return reflect.Value{}, conversionError(value, t)
+	}
+
+	if instance, ok := value.(*interpreter.LoxInstance); ok && instance.Native != nil && t != valueType {
+		native := reflect.ValueOf(instance.Native)
+		if native.Type().AssignableTo(t) {
+			return native, nil
+		}
+		if native.Type().Elem().AssignableTo(t) {
+			return native.Elem(), nil
+		}
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Type().AssignableTo(t) {
+		return v, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number, ok := value.(float64)
+		if !ok || float64(int64(number)) != number || reflect.Zero(t).OverflowInt(int64(number)) {
+			break
+		}
+		return reflect.ValueOf(int64(number)).Convert(t), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		number, ok := value.(float64)
+		if !ok || number < 0 || float64(uint64(number)) != number || reflect.Zero(t).OverflowUint(uint64(number)) {
+			break
+		}
+		return reflect.ValueOf(uint64(number)).Convert(t), nil
+	case reflect.Float32, reflect.Float64:
+		if number, ok := value.(float64); ok {
+			return reflect.ValueOf(number).Convert(t), nil
+		}
+	case reflect.String:
+		if s, ok := value.(string); ok {
+			return reflect.ValueOf(s).Convert(t), nil
+		}
+	case reflect.Bool:
+		if b, ok := value.(bool); ok {
+			return reflect.ValueOf(b).Convert(t), nil
+		}
+	case reflect.Slice:
+		list, ok := value.(*interpreter.LoxList)
+		if !ok {
+			break
+		}
+		slice := reflect.MakeSlice(t, len(list.Elements), len(list.Elements))
+		for i, element := range list.Elements {
+			converted, err := vm.toGo(element, t.Elem())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("element %d: %w", i, err)
+			}
+			slice.Index(i).Set(converted)
+		}
+		return slice, nil
+	}
+	return reflect.Value{}, conversionError(value, t)
+}
+
+func conversionError(value any, t reflect.Type) error {
+	if number, ok := value.(float64); ok && number != math.Trunc(number) {
+		return fmt.Errorf("cannot convert number %v to %s", number, t)
+	}
+	return fmt.Errorf("cannot convert %s to %s", interpreter.TypeName(value), t)
+}
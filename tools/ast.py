@@ -8,15 +8,26 @@ def generate_ast(output_dir: str) -> str:
         "Binary   : Left Expr, Operator *token.Token, Right Expr",
         "Call     : Callee Expr, Paren *token.Token, Arguments []Expr",
         "Get      : Object Expr, Name *token.Token",
+        "OptionalGet : Object Expr, Name *token.Token",
+        "Chain    : Expression Expr",
+        "Conditional : Condition Expr, ThenBranch Expr, ElseBranch Expr",
         "Set      : Object Expr, Name *token.Token, Value Expr",
         "Super    : Keyword *token.Token, Method *token.Token",
         "This     : Keyword *token.Token",
         "Grouping : Expression Expr",
         "Literal  : Value any",
+        "Interpolation : Parts []Expr",
         "Logical  : Left Expr, Operator *token.Token, Right Expr",
         "Unary    : Operator *token.Token, Right Expr",
         "Variable : Name *token.Token",
         "Assign   : Name *token.Token, Value Expr",
+        "Compound : Target Expr, Operator *token.Token, Value Expr",
+        "Update   : Target Expr, Operator *token.Token, Prefix bool",
+        "Lambda   : Keyword *token.Token, Function *Function",
+        "List     : Bracket *token.Token, Elements []Expr",
+        "Map      : Brace *token.Token, Keys []Expr, Values []Expr",
+        "Index    : Object Expr, Bracket *token.Token, Index Expr",
+        "SetIndex : Object Expr, Bracket *token.Token, Index Expr, Value Expr",
     ])
 
     define_ast(output_dir, "stmt", [
@@ -26,10 +37,14 @@ def generate_ast(output_dir: str) -> str:
         "Function   : Name *token.Token, Params []*token.Token, Body []Stmt",
         "Return    : Keyword *token.Token, Value Expr",
         "If        : Condition Expr, ThenBranch Stmt, ElseBranch Stmt",
-        "While     : Condition Expr, Body Stmt",
+        "While     : Keyword *token.Token, Condition Expr, Body Stmt, Increment Expr",
         "Break     : Keyword *token.Token",
+        "Continue  : Keyword *token.Token",
+        "Throw     : Keyword *token.Token, Value Expr",
+        "Try       : Body []Stmt, CatchName *token.Token, CatchBody []Stmt, FinallyBody []Stmt",
         "Print     : Expression Expr",
         "Var       : Name *token.Token, Initializer Expr",
+        "Import    : Keyword *token.Token, Path *token.Token, Alias *token.Token, Names []*token.Token",
     ])
 
 def define_ast(output_dir: str, file: str, types: list[str]) -> None: